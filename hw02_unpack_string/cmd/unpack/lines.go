@@ -0,0 +1,63 @@
+package main
+
+import (
+	"io"
+	"unicode/utf8"
+)
+
+// trackWindow is how far behind the read position line starts are kept.
+// The unpacker lags behind the reads by at most its bufio buffer, so errors
+// are always reported within this window.
+const trackWindow = 64 << 10
+
+// lineTracker passes reads through, remembering where recent lines start
+// so that a rune offset of an error can be turned into a line and column.
+type lineTracker struct {
+	r       io.Reader
+	runes   int
+	starts  []int
+	dropped int
+	// pending holds the start of a rune split between reads, buf is the
+	// scratch space joining it with the next read.
+	pending []byte
+	buf     []byte
+}
+
+func newLineTracker(r io.Reader) *lineTracker {
+	return &lineTracker{r: r, starts: []int{0}}
+}
+
+func (t *lineTracker) Read(p []byte) (int, error) {
+	n, err := t.r.Read(p)
+	t.buf = append(append(t.buf[:0], t.pending...), p[:n]...)
+	data := t.buf
+	// Runes are counted as bufio.Reader.ReadRune returns them: every byte
+	// of an invalid sequence is a rune of its own.
+	for len(data) > 0 {
+		if !utf8.FullRune(data) && err == nil {
+			break
+		}
+		r, size := utf8.DecodeRune(data)
+		data = data[size:]
+		t.runes++
+		if r == '\n' {
+			t.starts = append(t.starts, t.runes)
+		}
+	}
+	t.pending = append(t.pending[:0], data...)
+	for len(t.starts) > 1 && t.starts[1] < t.runes-trackWindow {
+		t.starts = t.starts[1:]
+		t.dropped++
+	}
+	return n, err
+}
+
+// lineStart returns the 1-based line containing the rune at offset and the
+// rune offset the line starts at.
+func (t *lineTracker) lineStart(offset int) (int, int) {
+	i := len(t.starts) - 1
+	for i > 0 && t.starts[i] > offset {
+		i--
+	}
+	return t.dropped + i + 1, t.starts[i]
+}
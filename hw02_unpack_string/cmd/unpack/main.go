@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"unicode/utf8"
+
+	hw02unpackstring "github.com/Nickolas990/otus_hw/hw02_unpack_string"
+)
+
+const usage = `Usage: %[1]s pack [-lines] [file ...]
+       %[1]s unpack [-lines] [-multidigit] [-graphemes] [-groups] [-max n] [-escape c] [file ...]
+
+Reads the files, or stdin when none are given, and writes the result to stdout.
+With -lines every line is processed separately and an invalid line is replaced
+by an empty one. Errors are reported as file:line:column.
+`
+
+var errUsage = errors.New("invalid usage")
+
+type command struct {
+	name   string
+	lines  bool
+	opts   hw02unpackstring.Options
+	stdin  io.Reader
+	stderr io.Writer
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command line and returns the process exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	cmd, files, err := parseArgs(args, stderr)
+	if err != nil {
+		if errors.Is(err, errUsage) {
+			fmt.Fprintf(stderr, usage, "unpack")
+		} else if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(stderr, err)
+		}
+		return 2
+	}
+	cmd.stdin, cmd.stderr = stdin, stderr
+
+	out := bufio.NewWriter(stdout)
+	status := 0
+	for _, name := range files {
+		if !cmd.processFile(name, out) {
+			status = 1
+		}
+	}
+	if err := out.Flush(); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	return status
+}
+
+func parseArgs(args []string, stderr io.Writer) (*command, []string, error) {
+	if len(args) == 0 || (args[0] != "pack" && args[0] != "unpack") {
+		return nil, nil, errUsage
+	}
+
+	cmd := &command{name: args[0]}
+	var escape string
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.BoolVar(&cmd.lines, "lines", false, "process every line separately")
+	if cmd.name == "unpack" {
+		fs.BoolVar(&cmd.opts.MultiDigit, "multidigit", false, "allow repeat counts of several digits")
+		fs.BoolVar(&cmd.opts.Graphemes, "graphemes", false, "repeat whole grapheme clusters")
+		fs.BoolVar(&cmd.opts.Groups, "groups", false, "allow repeating groups like (ab)3")
+		fs.IntVar(&cmd.opts.MaxSize, "max", 0, "maximum unpacked size in runes, 0 for no limit")
+		fs.StringVar(&escape, "escape", "", "escape character instead of backslash")
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return nil, nil, err
+	}
+
+	if escape != "" {
+		r, size := utf8.DecodeRuneInString(escape)
+		if size != len(escape) {
+			return nil, nil, fmt.Errorf("-escape must be a single character, got %q", escape)
+		}
+		cmd.opts.Escape = r
+	}
+
+	files := fs.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	return cmd, files, nil
+}
+
+// processFile writes the result for one input and reports whether it was valid.
+func (c *command) processFile(name string, out *bufio.Writer) bool {
+	in := c.stdin
+	if name == "-" {
+		name = "stdin"
+	} else {
+		f, err := os.Open(name)
+		if err != nil {
+			fmt.Fprintln(c.stderr, err)
+			return false
+		}
+		defer f.Close()
+		in = f
+	}
+
+	var err error
+	switch {
+	case c.lines:
+		return c.processLines(name, in, out)
+	case c.name == "pack":
+		err = pack(in, out)
+	default:
+		err = c.unpack(name, in, out)
+	}
+	if err != nil {
+		fmt.Fprintln(c.stderr, err)
+		return false
+	}
+	return true
+}
+
+func (c *command) processLines(name string, in io.Reader, out *bufio.Writer) bool {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(nil, 64<<20)
+
+	valid := true
+	for line := 1; scanner.Scan(); line++ {
+		var result string
+		if c.name == "pack" {
+			result = hw02unpackstring.Pack(scanner.Text())
+		} else {
+			var err error
+			result, err = hw02unpackstring.UnpackWithOptions(scanner.Text(), c.opts)
+			if err != nil {
+				fmt.Fprintln(c.stderr, positionError(name, line, err))
+				valid = false
+			}
+		}
+		out.WriteString(result)
+		out.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintf(c.stderr, "%s: %v\n", name, err)
+		return false
+	}
+	return valid
+}
+
+func pack(in io.Reader, out io.Writer) error {
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(out, hw02unpackstring.Pack(string(data)))
+	return err
+}
+
+func (c *command) unpack(name string, in io.Reader, out io.Writer) error {
+	lines := newLineTracker(in)
+	err := hw02unpackstring.UnpackStreamWithOptions(lines, out, c.opts)
+
+	var syntaxErr *hw02unpackstring.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		line, start := lines.lineStart(syntaxErr.Offset)
+		syntaxErr.Offset -= start
+		return positionError(name, line, syntaxErr)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// positionError formats err as file:line:column, where the column is taken
+// from the rune offset of a SyntaxError within the line.
+func positionError(name string, line int, err error) error {
+	var syntaxErr *hw02unpackstring.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		return fmt.Errorf("%s:%d: %w", name, line, err)
+	}
+	return fmt.Errorf("%s:%d:%d: %s: %s", name, line, syntaxErr.Offset+1,
+		hw02unpackstring.ErrInvalidString, syntaxErr.Reason)
+}
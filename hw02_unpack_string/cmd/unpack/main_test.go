@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	//nolint:depguard
+	"github.com/stretchr/testify/require"
+)
+
+func runCommand(t *testing.T, stdin string, args ...string) (string, string, int) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return stdout.String(), stderr.String(), code
+}
+
+func TestRun(t *testing.T) {
+	t.Run("unpack stdin", func(t *testing.T) {
+		stdout, stderr, code := runCommand(t, "a4bc2d5e\n3", "unpack")
+		require.Equal(t, 0, code, stderr)
+		require.Equal(t, "aaaabccddddde\n\n\n", stdout)
+	})
+
+	t.Run("pack stdin", func(t *testing.T) {
+		stdout, _, code := runCommand(t, "aaaabccddddde\n\n\n", "pack")
+		require.Equal(t, 0, code)
+		require.Equal(t, "a4bccd5e\n3", stdout)
+	})
+
+	t.Run("unpack options", func(t *testing.T) {
+		stdout, stderr, code := runCommand(t, "(ab)12/1", "unpack", "-groups", "-multidigit", "-escape", "/")
+		require.Equal(t, 0, code, stderr)
+		require.Equal(t, strings.Repeat("ab", 12)+"1", stdout)
+	})
+
+	t.Run("error position", func(t *testing.T) {
+		_, stderr, code := runCommand(t, "ab\nжж\\n", "unpack")
+		require.Equal(t, 1, code)
+		require.Equal(t, "stdin:2:4: invalid string: only digits and the escape character can be escaped\n", stderr)
+	})
+
+	t.Run("error position after many lines", func(t *testing.T) {
+		input := strings.Repeat("abc\n", 100_000) + "x33"
+		_, stderr, code := runCommand(t, input, "unpack")
+		require.Equal(t, 1, code)
+		require.Equal(t, "stdin:100001:3: invalid string: number instead of a digit\n", stderr)
+	})
+
+	t.Run("size limit", func(t *testing.T) {
+		_, stderr, code := runCommand(t, "a5", "unpack", "-max", "3")
+		require.Equal(t, 1, code)
+		require.Contains(t, stderr, "exceeds size limit")
+	})
+
+	t.Run("lines", func(t *testing.T) {
+		stdout, stderr, code := runCommand(t, "a3\n45\nb2\n", "unpack", "-lines")
+		require.Equal(t, 1, code)
+		require.Equal(t, "aaa\n\nbb\n", stdout)
+		require.Equal(t, "stdin:2:1: invalid string: digit without a character to repeat\n", stderr)
+	})
+
+	t.Run("position after invalid utf-8", func(t *testing.T) {
+		_, stderr, code := runCommand(t, "\x82\x82\n\\n", "unpack")
+		require.Equal(t, 1, code)
+		require.Equal(t, "stdin:2:2: invalid string: only digits and the escape character can be escaped\n", stderr)
+	})
+
+	t.Run("pack lines", func(t *testing.T) {
+		stdout, _, code := runCommand(t, "aaa\n45\n", "pack", "-lines")
+		require.Equal(t, 0, code)
+		require.Equal(t, "a3\n\\4\\5\n", stdout)
+	})
+
+	t.Run("files", func(t *testing.T) {
+		dir := t.TempDir()
+		first := filepath.Join(dir, "first.txt")
+		second := filepath.Join(dir, "second.txt")
+		require.NoError(t, os.WriteFile(first, []byte("a3"), 0o600))
+		require.NoError(t, os.WriteFile(second, []byte("b2"), 0o600))
+
+		stdout, _, code := runCommand(t, "", "unpack", first, second)
+		require.Equal(t, 0, code)
+		require.Equal(t, "aaabb", stdout)
+
+		_, stderr, code := runCommand(t, "", "unpack", filepath.Join(dir, "missing.txt"))
+		require.Equal(t, 1, code)
+		require.Contains(t, stderr, "missing.txt")
+	})
+
+	t.Run("usage", func(t *testing.T) {
+		_, stderr, code := runCommand(t, "", "frobnicate")
+		require.Equal(t, 2, code)
+		require.Contains(t, stderr, "Usage:")
+
+		_, stderr, code = runCommand(t, "", "unpack", "-escape", "ab")
+		require.Equal(t, 2, code)
+		require.Contains(t, stderr, "single character")
+	})
+}
+
+func TestLineTracker(t *testing.T) {
+	input := "ж\n\x82\xd0ж\n\xe2\x82"
+	for _, r := range []io.Reader{strings.NewReader(input), iotest.OneByteReader(strings.NewReader(input))} {
+		tracker := newLineTracker(r)
+		data, err := io.ReadAll(tracker)
+		require.NoError(t, err)
+		require.Equal(t, input, string(data))
+		require.Equal(t, []int{0, 2, 6}, tracker.starts)
+		require.Equal(t, 8, tracker.runes, "every byte of an invalid sequence is a rune")
+	}
+}
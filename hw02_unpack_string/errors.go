@@ -0,0 +1,59 @@
+package hw02unpackstring
+
+import "fmt"
+
+// Reason tells why an input string was rejected.
+type Reason int
+
+const (
+	ReasonLeadingDigit Reason = iota + 1
+	ReasonDoubleDigit
+	ReasonDanglingEscape
+	ReasonEscapedLetter
+	ReasonNonASCIIDigit
+	ReasonUnclosedGroup
+	ReasonUnopenedGroup
+	ReasonGroupTooDeep
+)
+
+func (r Reason) String() string {
+	switch r {
+	case ReasonLeadingDigit:
+		return "digit without a character to repeat"
+	case ReasonDoubleDigit:
+		return "number instead of a digit"
+	case ReasonDanglingEscape:
+		return "escape at the end of input"
+	case ReasonEscapedLetter:
+		return "only digits and the escape character can be escaped"
+	case ReasonNonASCIIDigit:
+		return "repeat count must be an ASCII digit"
+	case ReasonUnclosedGroup:
+		return "group is not closed"
+	case ReasonUnopenedGroup:
+		return "closing a group that was not opened"
+	case ReasonGroupTooDeep:
+		return "groups nested too deep"
+	default:
+		return fmt.Sprintf("Reason(%d)", int(r))
+	}
+}
+
+// SyntaxError describes an invalid input string. It matches ErrInvalidString
+// with errors.Is.
+type SyntaxError struct {
+	// Offset is the position of Char in the input, counted in runes.
+	Offset int
+	// ByteOffset is the same position counted in bytes.
+	ByteOffset int
+	Char       rune
+	Reason     Reason
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%v: %s: %q at rune %d", ErrInvalidString, e.Reason, e.Char, e.Offset)
+}
+
+func (e *SyntaxError) Unwrap() error {
+	return ErrInvalidString
+}
@@ -0,0 +1,45 @@
+package hw02unpackstring
+
+import (
+	"errors"
+	"testing"
+
+	//nolint:depguard
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyntaxError(t *testing.T) {
+	tests := []struct {
+		input    string
+		opts     Options
+		expected SyntaxError
+	}{
+		{input: "3abc", expected: SyntaxError{Offset: 0, Char: '3', Reason: ReasonLeadingDigit}},
+		{input: "aaa10b", expected: SyntaxError{Offset: 4, ByteOffset: 4, Char: '0', Reason: ReasonDoubleDigit}},
+		{input: `qw\ne`, expected: SyntaxError{Offset: 3, ByteOffset: 3, Char: 'n', Reason: ReasonEscapedLetter}},
+		{input: `aaa\`, expected: SyntaxError{Offset: 3, ByteOffset: 3, Char: '\\', Reason: ReasonDanglingEscape}},
+		{input: "ж٣", expected: SyntaxError{Offset: 1, ByteOffset: 2, Char: '٣', Reason: ReasonNonASCIIDigit}},
+		{
+			input:    "жж§",
+			opts:     Options{Escape: '§'},
+			expected: SyntaxError{Offset: 2, ByteOffset: 4, Char: '§', Reason: ReasonDanglingEscape},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.input, func(t *testing.T) {
+			_, err := UnpackWithOptions(tc.input, tc.opts)
+			require.ErrorIs(t, err, ErrInvalidString)
+
+			var syntaxErr *SyntaxError
+			require.True(t, errors.As(err, &syntaxErr), "actual error %q", err)
+			require.Equal(t, tc.expected, *syntaxErr)
+		})
+	}
+
+	t.Run("message", func(t *testing.T) {
+		_, err := Unpack("aaa10b")
+		require.EqualError(t, err, `invalid string: number instead of a digit: '0' at rune 4`)
+	})
+}
@@ -0,0 +1,139 @@
+package hw02unpackstring
+
+import "unicode"
+
+// The rules below follow the extended grapheme cluster boundaries of UAX #29
+// closely enough for the unpacker: combining marks, emoji modifiers and ZWJ
+// sequences, regional indicator pairs and Hangul syllables. Prepend characters
+// are not supported.
+
+const (
+	zwnj = '\u200C'
+	zwj  = '\u200D'
+)
+
+var (
+	emojiModifiers    = &unicode.RangeTable{R32: []unicode.Range32{{Lo: 0x1F3FB, Hi: 0x1F3FF, Stride: 1}}}
+	tags              = &unicode.RangeTable{R32: []unicode.Range32{{Lo: 0xE0020, Hi: 0xE007F, Stride: 1}}}
+	regionalIndicator = &unicode.RangeTable{R32: []unicode.Range32{{Lo: 0x1F1E6, Hi: 0x1F1FF, Stride: 1}}}
+
+	// pictographic approximates the Extended_Pictographic property.
+	pictographic = &unicode.RangeTable{
+		R16: []unicode.Range16{
+			{Lo: 0x00A9, Hi: 0x00AE, Stride: 5},
+			{Lo: 0x203C, Hi: 0x2049, Stride: 13},
+			{Lo: 0x2122, Hi: 0x2139, Stride: 23},
+			{Lo: 0x2194, Hi: 0x21AA, Stride: 1},
+			{Lo: 0x2300, Hi: 0x23FF, Stride: 1},
+			{Lo: 0x25AA, Hi: 0x25FE, Stride: 1},
+			{Lo: 0x2600, Hi: 0x27BF, Stride: 1},
+			{Lo: 0x2934, Hi: 0x2935, Stride: 1},
+			{Lo: 0x2B05, Hi: 0x2B55, Stride: 1},
+			{Lo: 0x3030, Hi: 0x303D, Stride: 13},
+			{Lo: 0x3297, Hi: 0x3299, Stride: 2},
+		},
+		R32: []unicode.Range32{
+			{Lo: 0x1F000, Hi: 0x1F1E5, Stride: 1},
+			{Lo: 0x1F200, Hi: 0x1F3FA, Stride: 1},
+			{Lo: 0x1F400, Hi: 0x1FAFF, Stride: 1},
+		},
+	}
+)
+
+type hangulType int
+
+const (
+	hangulNone hangulType = iota
+	hangulL
+	hangulV
+	hangulT
+	hangulLV
+	hangulLVT
+)
+
+// extendsCluster reports whether r continues the grapheme cluster formed by
+// the runes in cluster.
+func extendsCluster(cluster []rune, r rune) bool {
+	if len(cluster) == 0 {
+		return false
+	}
+	last := cluster[len(cluster)-1]
+
+	switch {
+	case last == '\r' && r == '\n':
+		return true
+	case isControl(last) || isControl(r):
+		return false
+	case extendsHangul(hangul(last), hangul(r)):
+		return true
+	case isExtend(r) || r == zwj || unicode.Is(unicode.Mc, r):
+		return true
+	case last == zwj && unicode.Is(pictographic, r):
+		return startsWithPictographic(cluster)
+	case unicode.Is(regionalIndicator, r):
+		return trailingRegionalIndicators(cluster)%2 == 1
+	}
+	return false
+}
+
+func isControl(r rune) bool {
+	switch {
+	case r == zwnj || r == zwj || unicode.Is(tags, r):
+		return false
+	case r == '\u2028' || r == '\u2029':
+		return true
+	}
+	return unicode.In(r, unicode.Cc, unicode.Cf)
+}
+
+func isExtend(r rune) bool {
+	return r == zwnj || unicode.In(r, unicode.Mn, unicode.Me, emojiModifiers, tags)
+}
+
+// startsWithPictographic checks for the ExtPict Extend* ZWJ shape required
+// before a pictograph can join the cluster.
+func startsWithPictographic(cluster []rune) bool {
+	i := len(cluster) - 2
+	for i >= 0 && isExtend(cluster[i]) {
+		i--
+	}
+	return i >= 0 && unicode.Is(pictographic, cluster[i])
+}
+
+func trailingRegionalIndicators(cluster []rune) int {
+	n := 0
+	for i := len(cluster) - 1; i >= 0 && unicode.Is(regionalIndicator, cluster[i]); i-- {
+		n++
+	}
+	return n
+}
+
+func hangul(r rune) hangulType {
+	switch {
+	case r >= 0x1100 && r <= 0x115F, r >= 0xA960 && r <= 0xA97C:
+		return hangulL
+	case r >= 0x1160 && r <= 0x11A7, r >= 0xD7B0 && r <= 0xD7C6:
+		return hangulV
+	case r >= 0x11A8 && r <= 0x11FF, r >= 0xD7CB && r <= 0xD7FB:
+		return hangulT
+	case r >= 0xAC00 && r <= 0xD7A3:
+		if (r-0xAC00)%28 == 0 {
+			return hangulLV
+		}
+		return hangulLVT
+	}
+	return hangulNone
+}
+
+func extendsHangul(prev, next hangulType) bool {
+	switch prev {
+	case hangulL:
+		return next == hangulL || next == hangulV || next == hangulLV || next == hangulLVT
+	case hangulLV, hangulV:
+		return next == hangulV || next == hangulT
+	case hangulLVT, hangulT:
+		return next == hangulT
+	case hangulNone:
+	}
+	return false
+}
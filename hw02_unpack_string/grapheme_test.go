@@ -0,0 +1,65 @@
+package hw02unpackstring
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	//nolint:depguard
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnpackGraphemes(t *testing.T) {
+	const (
+		eAcute   = "e\u0301"
+		family   = "\U0001F468\u200D\U0001F469\u200D\U0001F467"
+		thumbsUp = "\U0001F44D\U0001F3FD"
+		flagRU   = "\U0001F1F7\U0001F1FA"
+		flagUS   = "\U0001F1FA\U0001F1F8"
+		keycap   = "3\uFE0F\u20E3"
+		hangul   = "\u1112\u1161\u11AB"
+	)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "plain", input: "a4bc2d5e", expected: "aaaabccddddde"},
+		{name: "combining mark", input: eAcute + "3", expected: strings.Repeat(eAcute, 3)},
+		{name: "several marks", input: "a\u0323\u03012b", expected: "a\u0323\u0301a\u0323\u0301b"},
+		{name: "emoji modifier", input: thumbsUp + "2", expected: thumbsUp + thumbsUp},
+		{name: "zwj sequence", input: family + "2", expected: family + family},
+		{name: "flags", input: flagRU + flagUS + "2", expected: flagRU + flagUS + flagUS},
+		{name: "escaped keycap", input: `\` + keycap + "2", expected: keycap + keycap},
+		{name: "hangul jamo", input: hangul + "2", expected: hangul + hangul},
+		{name: "crlf", input: "\r\n2", expected: "\r\n\r\n"},
+		{name: "mark after count starts a new character", input: "a2\u03012", expected: "aa\u0301\u0301"},
+		{name: "zero", input: family + "0" + eAcute, expected: eAcute},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			opts := Options{Graphemes: true}
+			result, err := UnpackWithOptions(tc.input, opts)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, result)
+
+			var out bytes.Buffer
+			require.NoError(t, UnpackStreamWithOptions(strings.NewReader(tc.input), &out, opts))
+			require.Equal(t, tc.expected, out.String())
+		})
+	}
+
+	t.Run("rune mode repeats the last rune", func(t *testing.T) {
+		result, err := Unpack(eAcute + "3")
+		require.NoError(t, err)
+		require.Equal(t, "e\u0301\u0301\u0301", result)
+	})
+
+	t.Run("size limit counts runes", func(t *testing.T) {
+		_, err := UnpackWithOptions(eAcute+"3", Options{Graphemes: true, MaxSize: 5})
+		require.ErrorIs(t, err, ErrOutputTooLarge)
+	})
+}
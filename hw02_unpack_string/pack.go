@@ -0,0 +1,54 @@
+package hw02unpackstring
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const maxRepeat = 9
+
+// Pack is the inverse of Unpack: it run-length encodes input into the
+// shortest string for which Unpack returns input again. Invalid UTF-8
+// bytes are encoded as utf8.RuneError.
+func Pack(input string) string {
+	var result strings.Builder
+	result.Grow(len(input))
+
+	for len(input) > 0 {
+		r, size := utf8.DecodeRuneInString(input)
+		input = input[size:]
+		n := 1
+		for len(input) > 0 {
+			next, size := utf8.DecodeRuneInString(input)
+			if next != r {
+				break
+			}
+			input = input[size:]
+			n++
+		}
+		for ; n > maxRepeat; n -= maxRepeat {
+			writeRun(&result, r, maxRepeat)
+		}
+		writeRun(&result, r, n)
+	}
+	return result.String()
+}
+
+func writeRun(sb *strings.Builder, r rune, n int) {
+	needsEscape := unicode.IsDigit(r) || r == defaultEscape
+	// "aa" is as short as "a2" and reads better, "\1\1" is longer than "\12".
+	if n <= 2 && !needsEscape {
+		for i := 0; i < n; i++ {
+			sb.WriteRune(r)
+		}
+		return
+	}
+	if needsEscape {
+		sb.WriteRune(defaultEscape)
+	}
+	sb.WriteRune(r)
+	if n > 1 {
+		sb.WriteByte(byte('0' + n))
+	}
+}
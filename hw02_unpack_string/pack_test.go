@@ -0,0 +1,75 @@
+package hw02unpackstring
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	//nolint:depguard
+	"github.com/stretchr/testify/require"
+)
+
+func TestPack(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "", expected: ""},
+		{input: "abcd", expected: "abcd"},
+		{input: "aaaabccddddde", expected: "a4bccd5e"},
+		{input: "aaabbb", expected: "a3b3"},
+		{input: "aaaaaaaaaaaa", expected: "a9a3"},
+		{input: "aaaaaaaaaa", expected: "a9a"},
+		{input: "qwe45", expected: `qwe\4\5`},
+		{input: "qwe44444", expected: `qwe\45`},
+		{input: `qwe\\\\\`, expected: `qwe\\5`},
+		{input: `qwe\3`, expected: `qwe\\\3`},
+		{input: "d\n\n\n\n\nabc", expected: "d\n5abc"},
+		{input: "ééé", expected: "é3"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.input, func(t *testing.T) {
+			packed := Pack(tc.input)
+			require.Equal(t, tc.expected, packed)
+
+			unpacked, err := Unpack(packed)
+			require.NoError(t, err)
+			require.Equal(t, tc.input, unpacked)
+		})
+	}
+}
+
+func TestPackRoundTrip(t *testing.T) {
+	roundTrip := func(s string) bool {
+		unpacked, err := Unpack(Pack(s))
+		return err == nil && unpacked == s
+	}
+
+	t.Run("arbitrary unicode", func(t *testing.T) {
+		require.NoError(t, quick.Check(roundTrip, &quick.Config{MaxCount: 2000}))
+	})
+
+	t.Run("long runs", func(t *testing.T) {
+		alphabet := []rune("a1\\é٣\n 😀")
+		generate := func(values []reflect.Value, rnd *rand.Rand) {
+			var sb strings.Builder
+			for i := rnd.Intn(20); i > 0; i-- {
+				sb.WriteString(strings.Repeat(string(alphabet[rnd.Intn(len(alphabet))]), rnd.Intn(30)))
+			}
+			values[0] = reflect.ValueOf(sb.String())
+		}
+		cfg := &quick.Config{MaxCount: 2000, Values: generate}
+		require.NoError(t, quick.Check(roundTrip, cfg))
+	})
+
+	t.Run("escaping at most doubles the length", func(t *testing.T) {
+		shorter := func(s string) bool {
+			return len([]rune(Pack(s))) <= len([]rune(s))*2
+		}
+		require.NoError(t, quick.Check(shorter, nil))
+	})
+}
@@ -0,0 +1,146 @@
+package hw02unpackstring
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+// OffsetError reports the byte offset of the first invalid sequence in a stream.
+type OffsetError struct {
+	Offset int64
+	Err    error
+}
+
+func (e *OffsetError) Error() string {
+	return fmt.Sprintf("at byte %d: %v", e.Offset, e.Err)
+}
+
+func (e *OffsetError) Unwrap() error {
+	return e.Err
+}
+
+// UnpackStream unpacks r into w without holding the whole input or output in memory.
+func UnpackStream(r io.Reader, w io.Writer) error {
+	return UnpackStreamWithOptions(r, w, Options{})
+}
+
+func UnpackStreamWithOptions(r io.Reader, w io.Writer, opts Options) error {
+	_, err := io.Copy(w, NewReaderWithOptions(r, opts))
+	return err
+}
+
+// Reader is an io.Reader returning the unpacked contents of the underlying reader.
+type Reader struct {
+	src     *bufio.Reader
+	u       *unpacker
+	opts    Options
+	offset  int
+	run     []byte
+	runLen  int
+	pending []byte
+	err     error
+}
+
+func NewReader(r io.Reader) *Reader {
+	return NewReaderWithOptions(r, Options{})
+}
+
+func NewReaderWithOptions(r io.Reader, opts Options) *Reader {
+	rd := &Reader{src: bufio.NewReader(r), opts: opts}
+	rd.u = newUnpacker(opts, func(char []rune, n int) error {
+		rd.run = rd.run[:0]
+		for _, r := range char {
+			rd.run = utf8.AppendRune(rd.run, r)
+		}
+		rd.runLen = n
+		return nil
+	})
+	if err := opts.validate(); err != nil {
+		rd.err = err
+	}
+	return rd
+}
+
+func (rd *Reader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		switch {
+		case len(rd.pending) > 0:
+			c := copy(p[n:], rd.pending)
+			rd.pending = rd.pending[c:]
+			n += c
+		case rd.runLen > 0:
+			c := copy(p[n:], rd.run)
+			rd.pending = rd.run[c:]
+			rd.runLen--
+			n += c
+		case rd.err != nil:
+			if n > 0 {
+				return n, nil
+			}
+			return 0, rd.err
+		case n > 0 && rd.src.Buffered() == 0:
+			// Return what is unpacked instead of waiting for more input.
+			return n, nil
+		default:
+			rd.step()
+		}
+	}
+	return n, nil
+}
+
+// WriteTo implements io.WriterTo. It keeps io.Copy from handing unpacking
+// errors to the ReadFrom method of w, where they may stick, as in bufio.Writer.
+func (rd *Reader) WriteTo(w io.Writer) (int64, error) {
+	buf := make([]byte, 32<<10)
+	var written int64
+	for {
+		n, err := rd.Read(buf)
+		if n > 0 {
+			m, werr := w.Write(buf[:n])
+			written += int64(m)
+			if werr != nil {
+				return written, werr
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return written, nil
+		}
+		if err != nil {
+			return written, err
+		}
+	}
+}
+
+// step feeds the next input rune to the state machine.
+func (rd *Reader) step() {
+	r, size, err := rd.src.ReadRune()
+	if errors.Is(err, io.EOF) {
+		rd.err = io.EOF
+		if err := rd.u.finish(); err != nil {
+			rd.err = rd.offsetError(err)
+		}
+		return
+	}
+	if err != nil {
+		rd.err = err
+		return
+	}
+	if err := rd.u.feed(r, rd.offset); err != nil {
+		rd.err = rd.offsetError(err)
+		return
+	}
+	rd.offset += size
+}
+
+func (rd *Reader) offsetError(err error) error {
+	offset := rd.offset
+	var syntaxErr *SyntaxError
+	if errors.As(err, &syntaxErr) {
+		offset = syntaxErr.ByteOffset
+	}
+	return &OffsetError{Offset: int64(offset), Err: err}
+}
@@ -0,0 +1,115 @@
+package hw02unpackstring
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	//nolint:depguard
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnpackStream(t *testing.T) {
+	inputs := []string{
+		"", "a4bc2d5e", "abccd", "aaa0b", `qwe\4\5`, `qwe\45`, `qwe\\5`, `qwe\\\3`,
+		"d\n5abc", "привет3 мир2😀5",
+	}
+
+	for _, input := range inputs {
+		input := input
+		t.Run(input, func(t *testing.T) {
+			expected, err := Unpack(input)
+			require.NoError(t, err)
+
+			var out bytes.Buffer
+			err = UnpackStream(iotest.OneByteReader(strings.NewReader(input)), &out)
+			require.NoError(t, err)
+			require.Equal(t, expected, out.String())
+		})
+	}
+}
+
+func TestUnpackStreamOffset(t *testing.T) {
+	tests := []struct {
+		input  string
+		opts   Options
+		offset int64
+		err    error
+	}{
+		{input: "3abc", offset: 0, err: ErrInvalidString},
+		{input: "aaa10b", offset: 4, err: ErrInvalidString},
+		{input: `qw\ne`, offset: 3, err: ErrInvalidString},
+		{input: `ab\`, offset: 2, err: ErrInvalidString},
+		{input: "привет\\", offset: 12, err: ErrInvalidString},
+		{input: "ab§", opts: Options{Escape: '§'}, offset: 2, err: ErrInvalidString},
+		{input: "ab5c", opts: Options{MaxSize: 4}, offset: 2, err: ErrOutputTooLarge},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.input, func(t *testing.T) {
+			err := UnpackStreamWithOptions(strings.NewReader(tc.input), io.Discard, tc.opts)
+
+			var offsetErr *OffsetError
+			require.True(t, errors.As(err, &offsetErr), "actual error %q", err)
+			require.Equal(t, tc.offset, offsetErr.Offset)
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+}
+
+func TestReader(t *testing.T) {
+	t.Run("small reads", func(t *testing.T) {
+		r := NewReader(strings.NewReader("ж5щ2"))
+		require.NoError(t, iotest.TestReader(r, []byte("жжжжжщщ")))
+	})
+
+	t.Run("long run with bounded input", func(t *testing.T) {
+		r := NewReaderWithOptions(strings.NewReader("a999999b"), Options{MultiDigit: true})
+		n, err := io.Copy(io.Discard, r)
+		require.NoError(t, err)
+		require.Equal(t, int64(1_000_000), n)
+	})
+
+	t.Run("reader error", func(t *testing.T) {
+		errBroken := errors.New("broken")
+		r := NewReader(io.MultiReader(strings.NewReader("a3b"), iotest.ErrReader(errBroken)))
+		data, err := io.ReadAll(r)
+		require.ErrorIs(t, err, errBroken)
+		require.Equal(t, "aaa", string(data))
+	})
+
+	t.Run("returns without waiting for more input", func(t *testing.T) {
+		pr, pw := io.Pipe()
+		defer pw.Close()
+		go pw.Write([]byte("a3\n"))
+
+		type result struct {
+			data string
+			err  error
+		}
+		done := make(chan result)
+		go func() {
+			buf := make([]byte, 1024)
+			n, err := NewReader(pr).Read(buf)
+			done <- result{string(buf[:n]), err}
+		}()
+
+		select {
+		case res := <-done:
+			require.NoError(t, res.err)
+			require.Equal(t, "aaa", res.data)
+		case <-time.After(time.Second):
+			t.Fatal("Read is blocked waiting for more input")
+		}
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		_, err := io.ReadAll(NewReaderWithOptions(strings.NewReader("a"), Options{Escape: '1'}))
+		require.ErrorIs(t, err, ErrInvalidOptions)
+	})
+}
@@ -2,78 +2,280 @@ package hw02unpackstring
 
 import (
 	"errors"
-	"strconv"
+	"math"
+	"strings"
 	"unicode"
 )
 
-var ErrInvalidString = errors.New("invalid string")
+var (
+	ErrInvalidString  = errors.New("invalid string")
+	ErrOutputTooLarge = errors.New("unpacked string exceeds size limit")
+	ErrInvalidOptions = errors.New("invalid unpack options")
+)
+
+const (
+	defaultEscape = '\\'
+	groupOpen     = '('
+	groupClose    = ')'
+)
+
+// Options configures UnpackWithOptions. The zero value gives the Unpack grammar.
+type Options struct {
+	// MultiDigit allows repeat counts longer than one digit, e.g. "a12".
+	MultiDigit bool
+	// MaxSize limits the unpacked string length in runes, 0 means no limit.
+	// With Groups it also bounds the unpacked content of every group.
+	MaxSize int
+	// Escape is the escape character, backslash when zero.
+	Escape rune
+	// Graphemes makes the repeat count apply to the whole user-perceived
+	// character (grapheme cluster) rather than to its last rune.
+	Graphemes bool
+	// Groups enables repeating parenthesized substrings, e.g. "(ab)3".
+	// Parentheses become escapable like digits.
+	Groups bool
+	// MaxDepth limits group nesting, DefaultMaxDepth when zero.
+	MaxDepth int
+}
+
+const DefaultMaxDepth = 32
+
+func (o Options) escape() rune {
+	if o.Escape == 0 {
+		return defaultEscape
+	}
+	return o.Escape
+}
+
+func (o Options) maxDepth() int {
+	if o.MaxDepth == 0 {
+		return DefaultMaxDepth
+	}
+	return o.MaxDepth
+}
+
+func (o Options) isGroupMark(r rune) bool {
+	return o.Groups && (r == groupOpen || r == groupClose)
+}
+
+func (o Options) validate() error {
+	if o.MaxSize < 0 || o.MaxDepth < 0 || unicode.IsDigit(o.escape()) || o.isGroupMark(o.escape()) {
+		return ErrInvalidOptions
+	}
+	return nil
+}
 
 func Unpack(input string) (string, error) {
+	return UnpackWithOptions(input, Options{})
+}
+
+func UnpackWithOptions(input string, opts Options) (string, error) {
+	if err := opts.validate(); err != nil {
+		return "", err
+	}
 	if input == "" {
 		return "", nil
 	}
 
-	var result []rune
-	var prev rune
-	var escaped bool
-
-	for i, r := range input {
-		if escaped {
-			if err := handleEscapedCharacter(r, &prev); err != nil {
-				return "", err
+	var result strings.Builder
+	u := newUnpacker(opts, func(char []rune, n int) error {
+		for i := 0; i < n; i++ {
+			for _, r := range char {
+				result.WriteRune(r)
 			}
-			escaped = false
-			continue
 		}
+		return nil
+	})
+	for i, r := range input {
+		if err := u.feed(r, i); err != nil {
+			return "", err
+		}
+	}
+	if err := u.finish(); err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
+
+// emitFunc receives the unpacked output as runs of n copies of char.
+// The char slice is only valid until emitFunc returns.
+type emitFunc func(char []rune, n int) error
 
-		if r == '\\' && i == len(input)-1 {
-			return "", ErrInvalidString
-		} else if r == '\\' {
-			escaped = true
-			appendPreviousCharacter(&result, &prev)
-			continue
+type position struct {
+	rune int
+	byte int
+}
+
+// group is an open parenthesized group collecting its unpacked content.
+type group struct {
+	content []rune
+	start   position
+}
+
+type unpacker struct {
+	emit    emitFunc
+	opts    Options
+	prev    []rune
+	isGroup bool
+	escaped bool
+	count   int
+	digits  int
+	written int
+	pos     position
+	escPos  position
+	groups  []group
+}
+
+func newUnpacker(opts Options, emit emitFunc) *unpacker {
+	return &unpacker{emit: emit, opts: opts}
+}
+
+// feed consumes the next input rune found at the given byte offset.
+func (u *unpacker) feed(r rune, offset int) error {
+	u.pos.byte = offset
+	err := u.step(r)
+	u.pos.rune++
+	return err
+}
+
+func (u *unpacker) step(r rune) error {
+	if u.escaped {
+		return u.handleEscapedCharacter(r)
+	}
+
+	switch {
+	case r == u.opts.escape():
+		if err := u.flush(); err != nil {
+			return err
 		}
+		u.escaped, u.escPos = true, u.pos
+	case unicode.IsDigit(r):
+		return u.handleDigit(r)
+	case u.opts.Groups && r == groupOpen:
+		return u.openGroup(r)
+	case u.opts.Groups && r == groupClose:
+		return u.closeGroup(r)
+	case u.opts.Graphemes && u.digits == 0 && !u.isGroup && extendsCluster(u.prev, r):
+		u.prev = append(u.prev, r)
+	default:
+		if err := u.flush(); err != nil {
+			return err
+		}
+		u.prev = append(u.prev, r)
+	}
+	return nil
+}
 
-		if unicode.IsDigit(r) {
-			if err := handleDigit(r, &result, &prev); err != nil {
-				return "", err
-			}
-		} else {
-			appendPreviousCharacter(&result, &prev)
-			prev = r
+func (u *unpacker) finish() error {
+	if u.escaped {
+		return &SyntaxError{
+			Offset:     u.escPos.rune,
+			ByteOffset: u.escPos.byte,
+			Char:       u.opts.escape(),
+			Reason:     ReasonDanglingEscape,
 		}
 	}
+	if err := u.flush(); err != nil {
+		return err
+	}
+	if len(u.groups) > 0 {
+		start := u.groups[0].start
+		return &SyntaxError{Offset: start.rune, ByteOffset: start.byte, Char: groupOpen, Reason: ReasonUnclosedGroup}
+	}
+	return nil
+}
 
-	appendPreviousCharacter(&result, &prev)
-	return string(result), nil
+func (u *unpacker) handleEscapedCharacter(r rune) error {
+	if !unicode.IsDigit(r) && r != u.opts.escape() && !u.opts.isGroupMark(r) {
+		return u.syntaxError(r, ReasonEscapedLetter)
+	}
+	u.prev = append(u.prev, r)
+	u.escaped = false
+	return nil
 }
 
-func handleEscapedCharacter(r rune, prev *rune) error {
-	if !unicode.IsDigit(r) && r != '\\' {
-		return ErrInvalidString
+func (u *unpacker) handleDigit(r rune) error {
+	switch {
+	case len(u.prev) == 0 && !u.isGroup:
+		return u.syntaxError(r, ReasonLeadingDigit)
+	case r < '0' || r > '9':
+		return u.syntaxError(r, ReasonNonASCIIDigit)
+	case u.digits > 0 && !u.opts.MultiDigit:
+		return u.syntaxError(r, ReasonDoubleDigit)
+	}
+	if u.count > (math.MaxInt32-9)/10 {
+		return ErrOutputTooLarge
+	}
+	u.count = u.count*10 + int(r-'0')
+	u.digits++
+	if u.opts.MaxSize > 0 && u.size()+u.count*len(u.prev) > u.opts.MaxSize {
+		return ErrOutputTooLarge
 	}
-	*prev = r
 	return nil
 }
 
-func handleDigit(r rune, result *[]rune, prev *rune) error {
-	if *prev == 0 {
-		return ErrInvalidString
+func (u *unpacker) openGroup(r rune) error {
+	if err := u.flush(); err != nil {
+		return err
+	}
+	if len(u.groups) == u.opts.maxDepth() {
+		return u.syntaxError(r, ReasonGroupTooDeep)
 	}
-	count, err := strconv.Atoi(string(r))
-	if err != nil {
+	u.groups = append(u.groups, group{start: u.pos})
+	return nil
+}
+
+// closeGroup makes the content of the innermost group the character
+// the following count applies to.
+func (u *unpacker) closeGroup(r rune) error {
+	if err := u.flush(); err != nil {
 		return err
 	}
-	for i := 0; i < count; i++ {
-		*result = append(*result, *prev)
+	if len(u.groups) == 0 {
+		return u.syntaxError(r, ReasonUnopenedGroup)
 	}
-	*prev = 0
+	last := len(u.groups) - 1
+	u.prev, u.isGroup = u.groups[last].content, true
+	u.groups = u.groups[:last]
 	return nil
 }
 
-func appendPreviousCharacter(result *[]rune, prev *rune) {
-	if *prev != 0 {
-		*result = append(*result, *prev)
-		*prev = 0
+func (u *unpacker) syntaxError(r rune, reason Reason) error {
+	return &SyntaxError{Offset: u.pos.rune, ByteOffset: u.pos.byte, Char: r, Reason: reason}
+}
+
+// size returns the length in runes of the output being written to, which is
+// the content of the innermost open group if there is one.
+func (u *unpacker) size() int {
+	if len(u.groups) > 0 {
+		return len(u.groups[len(u.groups)-1].content)
 	}
+	return u.written
+}
+
+// flush writes the pending character or group repeated by the count read after it.
+func (u *unpacker) flush() error {
+	if len(u.prev) == 0 && !u.isGroup {
+		return nil
+	}
+	n := 1
+	if u.digits > 0 {
+		n = u.count
+	}
+	if u.opts.MaxSize > 0 && u.size()+n*len(u.prev) > u.opts.MaxSize {
+		return ErrOutputTooLarge
+	}
+	if len(u.groups) > 0 {
+		g := &u.groups[len(u.groups)-1]
+		for i := 0; i < n; i++ {
+			g.content = append(g.content, u.prev...)
+		}
+	} else {
+		if err := u.emit(u.prev, n); err != nil {
+			return err
+		}
+		u.written += n * len(u.prev)
+	}
+	u.prev, u.isGroup, u.count, u.digits = u.prev[:0], false, 0, 0
+	return nil
 }
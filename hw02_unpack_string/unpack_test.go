@@ -2,6 +2,8 @@ package hw02unpackstring
 
 import (
 	"errors"
+	"io"
+	"strings"
 	"testing"
 
 	//nolint:depguard
@@ -79,3 +81,139 @@ func TestUnpackCustom(t *testing.T) {
 		})
 	}
 }
+
+func TestUnpackWithOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		opts     Options
+		expected string
+		err      error
+	}{
+		{name: "defaults", input: "a4bc2d5e", opts: Options{}, expected: "aaaabccddddde"},
+		{name: "multi digit", input: "a12b", opts: Options{MultiDigit: true}, expected: "aaaaaaaaaaaab"},
+		{name: "multi digit zero", input: "a10b00c", opts: Options{MultiDigit: true}, expected: "aaaaaaaaaac"},
+		{name: "multi digit escaped", input: `\1\23`, opts: Options{MultiDigit: true}, expected: "1222"},
+		{name: "multi digit leading", input: "12a", opts: Options{MultiDigit: true}, err: ErrInvalidString},
+		{name: "single digit rejects number", input: "a12", opts: Options{}, err: ErrInvalidString},
+		{name: "custom escape", input: "a/3/4/", opts: Options{Escape: '/'}, err: ErrInvalidString},
+		{name: "custom escape digits", input: "a/34//2", opts: Options{Escape: '/'}, expected: "a3333//"},
+		{name: "backslash is literal with custom escape", input: `a\2`, opts: Options{Escape: '/'}, expected: `a\\`},
+		{name: "within limit", input: "a5", opts: Options{MaxSize: 5}, expected: "aaaaa"},
+		{name: "over limit", input: "a5b", opts: Options{MaxSize: 5}, err: ErrOutputTooLarge},
+		{name: "huge count", input: "a99999999999", opts: Options{MultiDigit: true}, err: ErrOutputTooLarge},
+		{
+			name: "huge count over limit", input: "a9999",
+			opts: Options{MultiDigit: true, MaxSize: 100}, err: ErrOutputTooLarge,
+		},
+		{name: "digit escape", input: "a", opts: Options{Escape: '7'}, err: ErrInvalidOptions},
+		{name: "negative limit", input: "a", opts: Options{MaxSize: -1}, err: ErrInvalidOptions},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := UnpackWithOptions(tc.input, tc.opts)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestUnpackGroups(t *testing.T) {
+	opts := Options{Groups: true}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "(ab)3", expected: "ababab"},
+		{input: "(a(bc)2)2", expected: "abcbcabcbc"},
+		{input: "x(ab)", expected: "xab"},
+		{input: "(ab)0c", expected: "c"},
+		{input: "()3", expected: ""},
+		{input: "(a2b)2", expected: "aabaab"},
+		{input: `\(a\)2`, expected: "(a))"},
+		{input: `(\1)3`, expected: "111"},
+		{input: "((a)2)2", expected: "aaaa"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.input, func(t *testing.T) {
+			result, err := UnpackWithOptions(tc.input, opts)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, result)
+		})
+	}
+
+	t.Run("parentheses are literal by default", func(t *testing.T) {
+		result, err := Unpack("(ab)3")
+		require.NoError(t, err)
+		require.Equal(t, "(ab)))", result)
+
+		_, err = Unpack(`\(`)
+		require.ErrorIs(t, err, ErrInvalidString)
+	})
+
+	t.Run("multi digit group count", func(t *testing.T) {
+		result, err := UnpackWithOptions("(ab)12", Options{Groups: true, MultiDigit: true})
+		require.NoError(t, err)
+		require.Equal(t, strings.Repeat("ab", 12), result)
+	})
+
+	invalid := []struct {
+		input  string
+		opts   Options
+		reason Reason
+		offset int
+	}{
+		{input: "(ab", reason: ReasonUnclosedGroup, offset: 0},
+		{input: "x(a(b)", reason: ReasonUnclosedGroup, offset: 1},
+		{input: "ab)2", reason: ReasonUnopenedGroup, offset: 2},
+		{input: "(3)", reason: ReasonLeadingDigit, offset: 1},
+		{input: "(((a)))", opts: Options{MaxDepth: 2}, reason: ReasonGroupTooDeep, offset: 2},
+	}
+
+	for _, tc := range invalid {
+		tc := tc
+		t.Run(tc.input, func(t *testing.T) {
+			tc.opts.Groups = true
+			_, err := UnpackWithOptions(tc.input, tc.opts)
+			require.ErrorIs(t, err, ErrInvalidString)
+
+			var syntaxErr *SyntaxError
+			require.True(t, errors.As(err, &syntaxErr), "actual error %q", err)
+			require.Equal(t, tc.reason, syntaxErr.Reason)
+			require.Equal(t, tc.offset, syntaxErr.Offset)
+		})
+	}
+
+	t.Run("output limit", func(t *testing.T) {
+		_, err := UnpackWithOptions("((ab)9)9", Options{Groups: true, MaxSize: 100})
+		require.ErrorIs(t, err, ErrOutputTooLarge)
+
+		_, err = UnpackWithOptions("((ab)9)0", Options{Groups: true, MaxSize: 10})
+		require.ErrorIs(t, err, ErrOutputTooLarge)
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		var out strings.Builder
+		require.NoError(t, UnpackStreamWithOptions(strings.NewReader("(a(bc)2)2"), &out, opts))
+		require.Equal(t, "abcbcabcbc", out.String())
+
+		err := UnpackStreamWithOptions(strings.NewReader("жж(a"), io.Discard, opts)
+		var offsetErr *OffsetError
+		require.True(t, errors.As(err, &offsetErr), "actual error %q", err)
+		require.Equal(t, int64(4), offsetErr.Offset)
+	})
+
+	t.Run("group marks cannot be the escape", func(t *testing.T) {
+		_, err := UnpackWithOptions("a", Options{Groups: true, Escape: '('})
+		require.ErrorIs(t, err, ErrInvalidOptions)
+	})
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	hw03frequencyanalysis "github.com/Nickolas990/otus_hw/hw03_frequency_analysis"
+)
+
+const usage = `Usage: topwords [-n 10] [-tokenizer letters|letters-digits|whitespace]
+                [-stopwords file] [-format text|json|csv] [file ...]
+
+Prints the most frequent words of the files, or of stdin when none are given.
+`
+
+var tokenizers = map[string]hw03frequencyanalysis.Tokenizer{
+	"letters":        hw03frequencyanalysis.LettersTokenizer,
+	"letters-digits": hw03frequencyanalysis.LettersDigitsTokenizer,
+	"whitespace":     hw03frequencyanalysis.WhitespaceTokenizer,
+}
+
+type config struct {
+	n         int
+	tokenizer string
+	stopWords string
+	format    string
+	files     []string
+}
+
+type jsonWord struct {
+	Word  string `json:"word"`
+	Count int    `json:"count"`
+}
+
+type jsonReport struct {
+	Total    int        `json:"total"`
+	Distinct int        `json:"distinct"`
+	Words    []jsonWord `json:"words"`
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command line and returns the process exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	cfg, err := parseArgs(args, stderr)
+	if err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(stderr, err)
+		}
+		return 2
+	}
+
+	opts := hw03frequencyanalysis.StreamOptions{}
+	opts.Tokenizer = tokenizers[cfg.tokenizer]
+	if cfg.stopWords != "" {
+		data, err := os.ReadFile(cfg.stopWords)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		opts.StopWords = strings.Fields(string(data))
+	}
+
+	in, closeAll, err := openInputs(cfg.files, stdin)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	defer closeAll()
+
+	report, err := hw03frequencyanalysis.AnalyzeReader(in, cfg.n, opts)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	if err := printReport(stdout, cfg.format, report); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func parseArgs(args []string, stderr io.Writer) (config, error) {
+	var cfg config
+	fs := flag.NewFlagSet("topwords", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		fs.PrintDefaults()
+	}
+	fs.IntVar(&cfg.n, "n", 10, "number of words to print")
+	fs.StringVar(&cfg.tokenizer, "tokenizer", "letters", "letters, letters-digits or whitespace")
+	fs.StringVar(&cfg.stopWords, "stopwords", "", "file with words to skip, separated by white space")
+	fs.StringVar(&cfg.format, "format", "text", "output format: text, json or csv")
+	if err := fs.Parse(args); err != nil {
+		return cfg, err
+	}
+
+	if _, ok := tokenizers[cfg.tokenizer]; !ok {
+		return cfg, fmt.Errorf("unknown tokenizer %q", cfg.tokenizer)
+	}
+	switch cfg.format {
+	case "text", "json", "csv":
+	default:
+		return cfg, fmt.Errorf("unknown format %q", cfg.format)
+	}
+	cfg.files = fs.Args()
+	return cfg, nil
+}
+
+// openInputs joins the files into one reader, separating them by a newline so
+// that words at file boundaries do not stick together.
+func openInputs(names []string, stdin io.Reader) (io.Reader, func(), error) {
+	if len(names) == 0 {
+		return stdin, func() {}, nil
+	}
+
+	files := make([]*os.File, 0, len(names))
+	closeAll := func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}
+	readers := make([]io.Reader, 0, 2*len(names))
+	for _, name := range names {
+		f, err := os.Open(name)
+		if err != nil {
+			closeAll()
+			return nil, nil, err
+		}
+		files = append(files, f)
+		readers = append(readers, f, strings.NewReader("\n"))
+	}
+	return io.MultiReader(readers...), closeAll, nil
+}
+
+func printReport(w io.Writer, format string, report hw03frequencyanalysis.Report) error {
+	switch format {
+	case "json":
+		out := jsonReport{Total: report.Total, Distinct: report.Distinct, Words: make([]jsonWord, 0, len(report.Words))}
+		for _, wf := range report.Words {
+			out.Words = append(out.Words, jsonWord{Word: wf.Word, Count: wf.Frequency})
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(out)
+	case "csv":
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"word", "count"})
+		for _, wf := range report.Words {
+			cw.Write([]string{wf.Word, strconv.Itoa(wf.Frequency)})
+		}
+		cw.Flush()
+		return cw.Error()
+	default:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, wf := range report.Words {
+			fmt.Fprintf(tw, "%s\t%d\n", wf.Word, wf.Frequency)
+		}
+		return tw.Flush()
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	//nolint:depguard
+	"github.com/stretchr/testify/require"
+)
+
+const input = "cat and dog, one dog,two cats and one man"
+
+func runCommand(t *testing.T, stdin string, args ...string) (string, string, int) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return stdout.String(), stderr.String(), code
+}
+
+func TestRun(t *testing.T) {
+	t.Run("text", func(t *testing.T) {
+		stdout, stderr, code := runCommand(t, input, "-n", "3")
+		require.Equal(t, 0, code, stderr)
+		require.Equal(t, "and  2\ndog  2\none  2\n", stdout)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		stdout, _, code := runCommand(t, input, "-n", "1", "-format", "json")
+		require.Equal(t, 0, code)
+		require.JSONEq(t, `{"total": 10, "distinct": 7, "words": [{"word": "and", "count": 2}]}`, stdout)
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		stdout, _, code := runCommand(t, input, "-n", "2", "-format", "csv", "-tokenizer", "whitespace")
+		require.Equal(t, 0, code)
+		require.Equal(t, "word,count\nand,2\none,2\n", stdout)
+	})
+
+	t.Run("files and stop words", func(t *testing.T) {
+		dir := t.TempDir()
+		first := filepath.Join(dir, "first.txt")
+		second := filepath.Join(dir, "second.txt")
+		stopWords := filepath.Join(dir, "stop.txt")
+		require.NoError(t, os.WriteFile(first, []byte("one two"), 0o600))
+		require.NoError(t, os.WriteFile(second, []byte("two three"), 0o600))
+		require.NoError(t, os.WriteFile(stopWords, []byte("three\n"), 0o600))
+
+		stdout, stderr, code := runCommand(t, "", "-stopwords", stopWords, first, second)
+		require.Equal(t, 0, code, stderr)
+		require.Equal(t, "two  2\none  1\n", stdout)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, stderr, code := runCommand(t, input, "-format", "xml")
+		require.Equal(t, 2, code)
+		require.Contains(t, stderr, "unknown format")
+
+		_, stderr, code = runCommand(t, input, "-tokenizer", "bytes")
+		require.Equal(t, 2, code)
+		require.Contains(t, stderr, "unknown tokenizer")
+
+		_, _, code = runCommand(t, "", "missing.txt")
+		require.Equal(t, 1, code)
+	})
+}
@@ -0,0 +1,102 @@
+package hw03frequencyanalysis
+
+import (
+	"math"
+	"sort"
+)
+
+const DefaultSmoothing = 0.5
+
+// DiffOptions configures Diff.
+type DiffOptions struct {
+	Options
+	// MinSupport skips words seen fewer times than this in both texts together.
+	MinSupport int
+	// Smoothing is added to every count so that words missing from one of the
+	// texts get a finite score, DefaultSmoothing when zero.
+	Smoothing float64
+}
+
+// WordDrift is the change of a word frequency between two texts.
+type WordDrift struct {
+	Word   string
+	Before int
+	After  int
+	// Score is the base 2 logarithm of the ratio of the word relative
+	// frequencies after and before: 1 means twice as frequent.
+	Score float64
+}
+
+// Drift is the result of Diff.
+type Drift struct {
+	// Rising are the words gaining frequency, the largest score first.
+	Rising []WordDrift
+	// Falling are the words losing frequency, the smallest score first.
+	Falling []WordDrift
+}
+
+// Diff compares the word distributions of two texts and returns the k words
+// whose relative frequency rose the most and the k words whose frequency fell
+// the most. Ties are broken lexicographically, as in TopN.
+func Diff(before, after string, k int, opts DiffOptions) Drift {
+	beforeWords := processWords(before, opts.Options)
+	afterWords := processWords(after, opts.Options)
+	beforeCount := countMap(beforeWords)
+	afterCount := countMap(afterWords)
+
+	vocabulary := make(map[string]struct{}, len(beforeCount)+len(afterCount))
+	for word := range beforeCount {
+		vocabulary[word] = struct{}{}
+	}
+	for word := range afterCount {
+		vocabulary[word] = struct{}{}
+	}
+
+	smoothing := opts.Smoothing
+	if smoothing <= 0 {
+		smoothing = DefaultSmoothing
+	}
+	beforeTotal := float64(len(beforeWords)) + smoothing*float64(len(vocabulary))
+	afterTotal := float64(len(afterWords)) + smoothing*float64(len(vocabulary))
+
+	var drift Drift
+	for word := range vocabulary {
+		wd := WordDrift{Word: word, Before: beforeCount[word], After: afterCount[word]}
+		if wd.Before+wd.After < opts.MinSupport {
+			continue
+		}
+		wd.Score = math.Log2(((float64(wd.After) + smoothing) / afterTotal) /
+			((float64(wd.Before) + smoothing) / beforeTotal))
+		switch {
+		case wd.Score > 0:
+			drift.Rising = append(drift.Rising, wd)
+		case wd.Score < 0:
+			drift.Falling = append(drift.Falling, wd)
+		}
+	}
+
+	sortDrift(drift.Rising, func(a, b float64) bool { return a > b })
+	sortDrift(drift.Falling, func(a, b float64) bool { return a < b })
+	drift.Rising = topDrift(drift.Rising, k)
+	drift.Falling = topDrift(drift.Falling, k)
+	return drift
+}
+
+func sortDrift(drift []WordDrift, before func(a, b float64) bool) {
+	sort.Slice(drift, func(i, j int) bool {
+		if drift[i].Score == drift[j].Score {
+			return drift[i].Word < drift[j].Word
+		}
+		return before(drift[i].Score, drift[j].Score)
+	})
+}
+
+func topDrift(drift []WordDrift, k int) []WordDrift {
+	if k < 0 {
+		k = 0
+	}
+	if k > len(drift) {
+		k = len(drift)
+	}
+	return drift[:k:k]
+}
@@ -0,0 +1,71 @@
+package hw03frequencyanalysis
+
+import (
+	"math"
+	"testing"
+
+	//nolint:depguard
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	before := "cache cache cache list list queue mutex"
+	after := "cache list list list list generic generic mutex"
+
+	t.Run("rising and falling", func(t *testing.T) {
+		drift := Diff(before, after, 10, DiffOptions{})
+
+		rising := make([]string, 0, len(drift.Rising))
+		for _, wd := range drift.Rising {
+			rising = append(rising, wd.Word)
+		}
+		falling := make([]string, 0, len(drift.Falling))
+		for _, wd := range drift.Falling {
+			falling = append(falling, wd.Word)
+		}
+
+		require.Equal(t, []string{"generic", "list"}, rising)
+		// "mutex" is as frequent in absolute numbers, but the text grew.
+		require.Equal(t, []string{"queue", "cache", "mutex"}, falling)
+
+		generic := drift.Rising[0]
+		require.Equal(t, 0, generic.Before)
+		require.Equal(t, 2, generic.After)
+		// Five words in the vocabulary, smoothed by 0.5 each.
+		require.InDelta(t, math.Log2((2.5/10.5)/(0.5/9.5)), generic.Score, 1e-9)
+	})
+
+	t.Run("unchanged words are left out", func(t *testing.T) {
+		drift := Diff("a b c", "c b a", 10, DiffOptions{})
+		require.Empty(t, drift.Rising)
+		require.Empty(t, drift.Falling)
+	})
+
+	t.Run("min support", func(t *testing.T) {
+		drift := Diff(before, after, 10, DiffOptions{MinSupport: 4})
+		require.Len(t, drift.Rising, 1)
+		require.Equal(t, "list", drift.Rising[0].Word)
+		require.Len(t, drift.Falling, 1)
+		require.Equal(t, "cache", drift.Falling[0].Word)
+	})
+
+	t.Run("k", func(t *testing.T) {
+		drift := Diff(before, after, 1, DiffOptions{})
+		require.Len(t, drift.Rising, 1)
+		require.Len(t, drift.Falling, 1)
+		require.Empty(t, Diff(before, after, 0, DiffOptions{}).Rising)
+	})
+
+	t.Run("ties are lexicographic", func(t *testing.T) {
+		drift := Diff("x", "x b a c", 10, DiffOptions{})
+		require.Equal(t, "a", drift.Rising[0].Word)
+		require.Equal(t, "b", drift.Rising[1].Word)
+		require.Equal(t, "c", drift.Rising[2].Word)
+	})
+
+	t.Run("options", func(t *testing.T) {
+		drift := Diff("Нога нога", "ноги ноги ноги рука", 10, DiffOptions{Options: Options{Stemmer: RussianStemmer}})
+		require.Equal(t, "рук", drift.Rising[0].Word)
+		require.Equal(t, "ног", drift.Falling[0].Word)
+	})
+}
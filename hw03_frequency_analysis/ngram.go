@@ -0,0 +1,28 @@
+package hw03frequencyanalysis
+
+import "strings"
+
+// TopNGrams returns the k most frequent sequences of n adjacent words with
+// their counts. The words of an n-gram are joined by a single space.
+func TopNGrams(text string, n, k int) []WordFrequency {
+	return AnalyzeNGrams(text, n, k, Options{}).Words
+}
+
+// AnalyzeNGrams is Analyze over n-grams: words are tokenized, normalized and
+// filtered as for Analyze, and every run of n consecutive remaining words is
+// counted as one entry.
+func AnalyzeNGrams(text string, n, k int, opts Options) Report {
+	words := processWords(text, opts)
+	if n < 1 || len(words) < n {
+		return Report{Words: []WordFrequency{}}
+	}
+
+	grams := make([]string, 0, len(words)-n+1)
+	for i := 0; i+n <= len(words); i++ {
+		grams = append(grams, strings.Join(words[i:i+n], " "))
+	}
+	wordFrequencies := countWords(grams)
+	sortFrequencies(wordFrequencies)
+
+	return Report{Words: topFrequencies(wordFrequencies, k), Total: len(grams), Distinct: len(wordFrequencies)}
+}
@@ -0,0 +1,43 @@
+package hw03frequencyanalysis
+
+import (
+	"testing"
+
+	//nolint:depguard
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopNGrams(t *testing.T) {
+	t.Run("bigrams", func(t *testing.T) {
+		input := "Винни-Пух любит мёд. Винни-Пух любит гулять, а Пятачок любит мёд"
+		expected := []WordFrequency{{"винни-пух любит", 2}, {"любит мёд", 2}, {"а пятачок", 1}}
+		require.Equal(t, expected, TopNGrams(input, 2, 3))
+	})
+
+	t.Run("trigrams", func(t *testing.T) {
+		input := "one two three one two three one two four"
+		expected := []WordFrequency{{"one two three", 2}, {"three one two", 2}, {"two three one", 2}, {"one two four", 1}}
+		require.Equal(t, expected, TopNGrams(input, 3, 10))
+	})
+
+	t.Run("unigrams match top10", func(t *testing.T) {
+		grams := TopNGrams(text, 1, 10)
+		require.Len(t, grams, 10)
+		for i, word := range Top10(text) {
+			require.Equal(t, word, grams[i].Word)
+		}
+	})
+
+	t.Run("too short", func(t *testing.T) {
+		require.Empty(t, TopNGrams("one two", 3, 10))
+		require.Empty(t, TopNGrams("one two", 0, 10))
+		require.Empty(t, TopNGrams("", 2, 10))
+	})
+
+	t.Run("options", func(t *testing.T) {
+		report := AnalyzeNGrams("the cat and the dog and the cat", 2, 1, Options{StopWords: []string{"the", "and"}})
+		require.Equal(t, []WordFrequency{{"cat dog", 1}}, report.Words)
+		require.Equal(t, 2, report.Total)
+		require.Equal(t, 2, report.Distinct)
+	})
+}
@@ -0,0 +1,125 @@
+package hw03frequencyanalysis
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	//nolint:depguard
+	"golang.org/x/text/unicode/norm"
+)
+
+// Stemmer reduces a normalized word to its stem.
+type Stemmer func(word string) string
+
+var (
+	// RussianStemmer strips common Russian inflection endings,
+	// so that "нога", "ногу" and "ноги" all become "ног".
+	RussianStemmer Stemmer = func(word string) string {
+		return stripSuffix(stripSuffix(word, russianReflexive, 3), russianEndings, 2)
+	}
+	// EnglishStemmer strips plural, past tense and gerund endings.
+	EnglishStemmer Stemmer = stemEnglish
+	// AutoStemmer picks RussianStemmer for Cyrillic words and EnglishStemmer for the rest.
+	AutoStemmer Stemmer = func(word string) string {
+		for _, r := range word {
+			if unicode.Is(unicode.Cyrillic, r) {
+				return RussianStemmer(word)
+			}
+		}
+		return EnglishStemmer(word)
+	}
+)
+
+var yoReplacer = strings.NewReplacer("ё", "е", "Ё", "Е")
+
+// Endings are ordered longest first, so that the longest match is stripped.
+var (
+	russianReflexive = []string{"ся", "сь"}
+	russianEndings   = []string{
+		"иями", "ями", "ами", "ого", "его", "ому", "ему",
+		"ыми", "ими", "ешь", "ете", "ите", "ишь",
+		"ый", "ий", "ой", "ая", "яя", "ое", "ее", "ые", "ие", "ую",
+		"юю", "ом", "ем", "ам", "ям", "ах", "ях", "ов", "ев", "ей",
+		"ых", "их", "ию", "ия", "ье", "ья", "ть", "ти", "ла", "ло",
+		"ли", "ет", "ут", "ют", "ит", "ат", "ят", "ал", "ил", "ел",
+		"а", "я", "о", "е", "ы", "и", "у", "ю", "ь", "й",
+	}
+)
+
+// stripSuffix removes the first matching suffix keeping at least minStem runes.
+func stripSuffix(word string, suffixes []string, minStem int) string {
+	for _, suffix := range suffixes {
+		stem, found := strings.CutSuffix(word, suffix)
+		if found && utf8.RuneCountInString(stem) >= minStem {
+			return stem
+		}
+	}
+	return word
+}
+
+func stemEnglish(word string) string {
+	word = strings.TrimSuffix(word, "'s")
+	switch {
+	case strings.HasSuffix(word, "sses"):
+		word = strings.TrimSuffix(word, "es")
+	case strings.HasSuffix(word, "ies") && len(word) > 4:
+		word = strings.TrimSuffix(word, "ies") + "y"
+	case strings.HasSuffix(word, "s") && len(word) > 3 && !strings.ContainsRune("siu", rune(word[len(word)-2])):
+		// "glass", "bus" and "analysis" are not plurals.
+		word = strings.TrimSuffix(word, "s")
+	}
+	for _, suffix := range []string{"ing", "ed"} {
+		if stem, found := strings.CutSuffix(word, suffix); found && len(stem) >= 3 && strings.ContainsAny(stem, "aeiouy") {
+			return undouble(stem)
+		}
+	}
+	return word
+}
+
+// undouble turns "stopp" left from "stopped" back into "stop".
+func undouble(stem string) string {
+	n := len(stem)
+	if n >= 2 && stem[n-1] == stem[n-2] && !strings.ContainsRune("lsz", rune(stem[n-1])) {
+		return stem[:n-1]
+	}
+	return stem
+}
+
+// prepare applies the normalization that has to see the whole text.
+func (o Options) prepare(text string) string {
+	if o.NFC {
+		return norm.NFC.String(text)
+	}
+	return text
+}
+
+// normalize runs the per word normalization pipeline.
+func (o Options) normalize(word string) string {
+	switch o.Case {
+	case CaseLower:
+		word = strings.ToLower(word)
+	case CaseFold:
+		word = strings.Map(foldRune, word)
+	case CaseKeep:
+	}
+	if o.FoldYo {
+		word = yoReplacer.Replace(word)
+	}
+	if o.TrimPunctuation {
+		word = trimPunctuation(word)
+	}
+	if o.Stemmer != nil {
+		word = o.Stemmer(word)
+	}
+	return word
+}
+
+// trimPunctuation strips punctuation, hyphens included, from both ends of a
+// word. A word made of punctuation only, like "-------", is kept as is.
+func trimPunctuation(word string) string {
+	if trimmed := strings.TrimFunc(word, unicode.IsPunct); trimmed != "" {
+		return trimmed
+	}
+	return word
+}
@@ -0,0 +1,86 @@
+package hw03frequencyanalysis
+
+import (
+	"testing"
+
+	//nolint:depguard
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalization(t *testing.T) {
+	t.Run("nfc", func(t *testing.T) {
+		input := "е\u0308ж \u0451ж"
+		require.Equal(t, []string{"е", "ж", "ёж"}, TopN(input, 10, Options{}))
+		require.Equal(t, []string{"ёж"}, TopN(input, 10, Options{NFC: true}))
+	})
+
+	t.Run("yo", func(t *testing.T) {
+		require.Equal(t, []string{"еж"}, TopN("ёж Ёж еж", 10, Options{FoldYo: true}))
+		require.Equal(t, []string{"еж"}, TopN("ёж еж", 10, Options{NFC: true, FoldYo: true}))
+	})
+
+	t.Run("punctuation", func(t *testing.T) {
+		input := "нога! нога, 'нога' -нога- какой-то какойто ------- -"
+		opts := Options{Tokenizer: WhitespaceTokenizer, TrimPunctuation: true}
+		require.Equal(t, []string{"нога", "-------", "какой-то", "какойто"}, TopN(input, 10, opts))
+	})
+
+	t.Run("stemmer", func(t *testing.T) {
+		input := "нога ногу ноги ногами Нога"
+		require.Equal(t, []string{"нога", "ногами", "ноги", "ногу"}, TopN(input, 10, Options{}))
+		require.Equal(t, []string{"ног"}, TopN(input, 10, Options{Stemmer: RussianStemmer}))
+	})
+
+	t.Run("pipeline", func(t *testing.T) {
+		input := "Ёлки, ёлка; ёлку! cats cat's -cat"
+		opts := Options{
+			Tokenizer:       WhitespaceTokenizer,
+			NFC:             true,
+			FoldYo:          true,
+			TrimPunctuation: true,
+			Stemmer:         AutoStemmer,
+		}
+		require.Equal(t, []string{"cat", "елк"}, TopN(input, 10, opts))
+	})
+}
+
+func TestStemmers(t *testing.T) {
+	russian := map[string]string{
+		"нога":       "ног",
+		"ногу":       "ног",
+		"ноги":       "ног",
+		"ногами":     "ног",
+		"красивый":   "красив",
+		"красивого":  "красив",
+		"учиться":    "учи",
+		"улыбнулась": "улыбну",
+		"он":         "он",
+		"я":          "я",
+	}
+	for word, stem := range russian {
+		require.Equal(t, stem, RussianStemmer(word), word)
+	}
+
+	english := map[string]string{
+		"cats":     "cat",
+		"classes":  "class",
+		"studies":  "study",
+		"running":  "run",
+		"stopped":  "stop",
+		"jumped":   "jump",
+		"falling":  "fall",
+		"bus":      "bus",
+		"glass":    "glass",
+		"is":       "is",
+		"red":      "red",
+		"wing":     "wing",
+		"analysis": "analysis",
+		"cat's":    "cat",
+	}
+	for word, stem := range english {
+		require.Equal(t, stem, EnglishStemmer(word), word)
+	}
+
+	require.Equal(t, "ног", AutoStemmer("ноги"))
+	require.Equal(t, "cat", AutoStemmer("cats"))
+}
@@ -0,0 +1,109 @@
+package hw03frequencyanalysis
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Tokenizer splits a text into words.
+type Tokenizer func(text string) []string
+
+var (
+	lettersRe       = regexp.MustCompile(`[\p{L}-]+`)
+	lettersDigitsRe = regexp.MustCompile(`[\p{L}\p{N}-]+`)
+)
+
+var (
+	// LettersTokenizer takes runs of letters and hyphens as words, as Top10 does.
+	LettersTokenizer Tokenizer = func(text string) []string {
+		return lettersRe.FindAllString(text, -1)
+	}
+	// LettersDigitsTokenizer also keeps digits inside words.
+	LettersDigitsTokenizer Tokenizer = func(text string) []string {
+		return lettersDigitsRe.FindAllString(text, -1)
+	}
+	// WhitespaceTokenizer splits on white space only, keeping punctuation in words.
+	WhitespaceTokenizer Tokenizer = strings.Fields
+)
+
+// CaseMode selects how letter case is normalized before counting.
+type CaseMode int
+
+const (
+	// CaseLower lowercases words, the Top10 behavior.
+	CaseLower CaseMode = iota
+	// CaseKeep counts "Нога" and "нога" as different words.
+	CaseKeep
+	// CaseFold applies Unicode simple case folding, so that e.g. "ς" and "σ" match.
+	CaseFold
+)
+
+// Options configures TopN. The zero value gives the Top10 behavior.
+type Options struct {
+	// Tokenizer splits the text into words, LettersTokenizer when nil.
+	Tokenizer Tokenizer
+	// StopWords are never counted. They are matched after case normalization.
+	StopWords []string
+	Case      CaseMode
+	// MinLength skips words shorter than this many runes.
+	MinLength int
+
+	// NFC brings the text to Unicode normalization form C before tokenizing,
+	// so that decomposed letters are not split into separate words.
+	NFC bool
+	// FoldYo replaces "ё" with "е".
+	FoldYo bool
+	// TrimPunctuation strips punctuation and hyphens around words.
+	TrimPunctuation bool
+	// Stemmer, when set, counts words by their stems.
+	Stemmer Stemmer
+}
+
+func (o Options) tokenizer() Tokenizer {
+	if o.Tokenizer == nil {
+		return LettersTokenizer
+	}
+	return o.Tokenizer
+}
+
+// foldRune maps r to the lowercase form of the smallest rune of its case
+// folding orbit, so that folded words stay lowercase.
+func foldRune(r rune) rune {
+	folded := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f < folded {
+			folded = f
+		}
+	}
+	return unicode.ToLower(folded)
+}
+
+// wordFilter drops the words that TopN must not count.
+type wordFilter struct {
+	stopWords map[string]struct{}
+	minLength int
+}
+
+func newWordFilter(opts Options) wordFilter {
+	f := wordFilter{minLength: opts.MinLength}
+	if len(opts.StopWords) > 0 {
+		f.stopWords = make(map[string]struct{}, len(opts.StopWords))
+		for _, word := range opts.StopWords {
+			f.stopWords[opts.normalize(word)] = struct{}{}
+		}
+	}
+	return f
+}
+
+func (f wordFilter) accepts(word string) bool {
+	// "-" is a dash, not a word.
+	if word == "-" || word == "" {
+		return false
+	}
+	if _, stop := f.stopWords[word]; stop {
+		return false
+	}
+	return f.minLength <= 0 || utf8.RuneCountInString(word) >= f.minLength
+}
@@ -0,0 +1,70 @@
+package hw03frequencyanalysis
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// AnalyzeParallel is Analyze spread over several goroutines: the text is
+// split into shards at white space, every worker counts its shard into its
+// own map and the maps are merged. The result is the same as Analyze as long
+// as the tokenizer does not produce words containing white space.
+// Non-positive workers means runtime.GOMAXPROCS(0).
+func AnalyzeParallel(text string, n, workers int, opts Options) Report {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	filter := newWordFilter(opts)
+	shards := splitShards(opts.prepare(text), workers)
+
+	counts := make([]map[string]int, len(shards))
+	totals := make([]int, len(shards))
+	var wg sync.WaitGroup
+	wg.Add(len(shards))
+	for i, shard := range shards {
+		go func(i int, shard string) {
+			defer wg.Done()
+			wordCount := make(map[string]int)
+			for _, token := range opts.tokenizer()(shard) {
+				word := opts.normalize(token)
+				if filter.accepts(word) {
+					wordCount[word]++
+					totals[i]++
+				}
+			}
+			counts[i] = wordCount
+		}(i, shard)
+	}
+	wg.Wait()
+
+	merged := counts[0]
+	total := totals[0]
+	for i := 1; i < len(counts); i++ {
+		for word, count := range counts[i] {
+			merged[word] += count
+		}
+		total += totals[i]
+	}
+
+	wordFrequencies := frequencies(merged)
+	sortFrequencies(wordFrequencies)
+	return Report{Words: topFrequencies(wordFrequencies, n), Total: total, Distinct: len(wordFrequencies)}
+}
+
+// splitShards cuts text into at most parts pieces of similar size, moving
+// every cut forward to the next white space so that no word is split.
+func splitShards(text string, parts int) []string {
+	shards := make([]string, 0, parts)
+	size := len(text)/parts + 1
+	for len(text) > size && len(shards) < parts-1 {
+		cut := strings.IndexFunc(text[size:], unicode.IsSpace)
+		if cut < 0 {
+			break
+		}
+		shards = append(shards, text[:size+cut])
+		text = text[size+cut:]
+	}
+	return append(shards, text)
+}
@@ -0,0 +1,85 @@
+package hw03frequencyanalysis
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	//nolint:depguard
+	"github.com/stretchr/testify/require"
+)
+
+func randomText(words, vocabulary int) string {
+	rnd := rand.New(rand.NewSource(1))
+	separators := []string{" ", "\n", ", ", " - ", "\t"}
+	var sb strings.Builder
+	for i := 0; i < words; i++ {
+		// Zipf-like distribution gives plenty of ties among rare words.
+		fmt.Fprintf(&sb, "Слово%c%d", 'а'+rune(rnd.Intn(3)), rnd.Intn(1+rnd.Intn(vocabulary)))
+		sb.WriteString(separators[rnd.Intn(len(separators))])
+	}
+	return sb.String()
+}
+
+func TestAnalyzeParallel(t *testing.T) {
+	t.Run("same as analyze", func(t *testing.T) {
+		large := randomText(20_000, 500)
+		inputs := map[string]string{"empty": "", "text": text, "large": large}
+		options := map[string]Options{
+			"default":        {},
+			"letters digits": {Tokenizer: LettersDigitsTokenizer},
+			"whitespace":     {Tokenizer: WhitespaceTokenizer, Case: CaseKeep},
+			"filtered":       {StopWords: []string{"а", "и"}, MinLength: 2},
+		}
+
+		for inputName, input := range inputs {
+			for optsName, opts := range options {
+				for _, workers := range []int{0, 1, 2, 3, 8, 64} {
+					input, opts, workers := input, opts, workers
+					t.Run(fmt.Sprintf("%s/%s/%d", inputName, optsName, workers), func(t *testing.T) {
+						require.Equal(t, Analyze(input, 50, opts), AnalyzeParallel(input, 50, workers, opts))
+					})
+				}
+			}
+		}
+	})
+
+	t.Run("top10 order", func(t *testing.T) {
+		report := AnalyzeParallel(text, 10, 4, Options{})
+		words := make([]string, 0, len(report.Words))
+		for _, wf := range report.Words {
+			words = append(words, wf.Word)
+		}
+		require.Equal(t, Top10(text), words)
+	})
+}
+
+func TestSplitShards(t *testing.T) {
+	input := "one two three four five six"
+	for parts := 1; parts <= 10; parts++ {
+		shards := splitShards(input, parts)
+		require.LessOrEqual(t, len(shards), parts)
+		require.Equal(t, input, strings.Join(shards, ""))
+		for _, shard := range shards[1:] {
+			require.True(t, strings.HasPrefix(shard, " "), "shard %q starts inside a word", shard)
+		}
+	}
+}
+
+func BenchmarkAnalyze(b *testing.B) {
+	input := randomText(1_000_000, 10_000)
+	b.SetBytes(int64(len(input)))
+
+	b.Run("sequential", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Analyze(input, 10, Options{})
+		}
+	})
+
+	b.Run("parallel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			AnalyzeParallel(input, 10, 0, Options{})
+		}
+	})
+}
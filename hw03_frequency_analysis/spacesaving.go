@@ -0,0 +1,138 @@
+package hw03frequencyanalysis
+
+import "container/heap"
+
+// exactCounter counts every word in a map and switches to a Space-Saving
+// sketch once the map would hold more than maxWords words.
+type exactCounter struct {
+	counts   map[string]int
+	maxWords int
+	sketch   *spaceSaving
+}
+
+func newExactCounter(maxWords int) *exactCounter {
+	return &exactCounter{counts: make(map[string]int), maxWords: maxWords}
+}
+
+func (c *exactCounter) add(word string) {
+	if c.sketch != nil {
+		c.sketch.add(word)
+		return
+	}
+	if _, found := c.counts[word]; !found && c.maxWords > 0 && len(c.counts) == c.maxWords {
+		c.sketch = newSpaceSaving(c.counts)
+		c.counts = nil
+		c.sketch.add(word)
+		return
+	}
+	c.counts[word]++
+}
+
+func (c *exactCounter) frequencies() []WordFrequency {
+	if c.sketch != nil {
+		return c.sketch.frequencies()
+	}
+	return frequencies(c.counts)
+}
+
+func (c *exactCounter) maxError() int {
+	if c.sketch != nil {
+		return c.sketch.maxError()
+	}
+	return 0
+}
+
+// spaceSaving is the Space-Saving heavy hitters sketch (Metwally et al.):
+// a fixed set of counters where an unknown word takes over the smallest
+// counter. Counts are overestimated by at most the smallest counter, which
+// never exceeds total/len(counters).
+type spaceSaving struct {
+	counters counterHeap
+	index    map[string]int
+}
+
+type counter struct {
+	word  string
+	count int
+}
+
+// newSpaceSaving starts the sketch from exact counts, one counter per word.
+func newSpaceSaving(counts map[string]int) *spaceSaving {
+	s := &spaceSaving{
+		counters: counterHeap{counters: make([]counter, 0, len(counts))},
+		index:    make(map[string]int, len(counts)),
+	}
+	s.counters.index = s.index
+	for word, count := range counts {
+		s.index[word] = len(s.counters.counters)
+		s.counters.counters = append(s.counters.counters, counter{word, count})
+	}
+	heap.Init(&s.counters)
+	return s
+}
+
+func (s *spaceSaving) add(word string) {
+	if i, found := s.index[word]; found {
+		s.counters.counters[i].count++
+		heap.Fix(&s.counters, i)
+		return
+	}
+	if len(s.counters.counters) == 0 {
+		return
+	}
+	smallest := &s.counters.counters[0]
+	delete(s.index, smallest.word)
+	smallest.word = word
+	smallest.count++
+	s.index[word] = 0
+	heap.Fix(&s.counters, 0)
+}
+
+func (s *spaceSaving) frequencies() []WordFrequency {
+	wordFrequencies := make([]WordFrequency, 0, len(s.counters.counters))
+	for _, c := range s.counters.counters {
+		wordFrequencies = append(wordFrequencies, WordFrequency{c.word, c.count})
+	}
+	return wordFrequencies
+}
+
+func (s *spaceSaving) maxError() int {
+	if len(s.counters.counters) == 0 {
+		return 0
+	}
+	return s.counters.counters[0].count
+}
+
+// counterHeap is a min-heap of counters that keeps index pointing at the
+// position of every word.
+type counterHeap struct {
+	counters []counter
+	index    map[string]int
+}
+
+func (h *counterHeap) Len() int {
+	return len(h.counters)
+}
+
+func (h *counterHeap) Less(i, j int) bool {
+	return h.counters[i].count < h.counters[j].count
+}
+
+func (h *counterHeap) Swap(i, j int) {
+	h.counters[i], h.counters[j] = h.counters[j], h.counters[i]
+	h.index[h.counters[i].word] = i
+	h.index[h.counters[j].word] = j
+}
+
+func (h *counterHeap) Push(x interface{}) {
+	c := x.(counter)
+	h.index[c.word] = len(h.counters)
+	h.counters = append(h.counters, c)
+}
+
+func (h *counterHeap) Pop() interface{} {
+	last := h.counters[len(h.counters)-1]
+	h.counters = h.counters[:len(h.counters)-1]
+	delete(h.index, last.word)
+	return last
+}
@@ -0,0 +1,79 @@
+package hw03frequencyanalysis
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"unicode"
+)
+
+const DefaultChunkSize = 64 << 10
+
+// StreamOptions configures AnalyzeReader.
+type StreamOptions struct {
+	Options
+	// ChunkSize is how many bytes are tokenized at once, DefaultChunkSize when zero.
+	ChunkSize int
+	// MaxWords is the memory budget in distinct words, 0 means no limit.
+	// Once the text has more distinct words, counting continues with a
+	// Space-Saving sketch of MaxWords counters and the report is approximate.
+	MaxWords int
+}
+
+// AnalyzeReader is Analyze over a stream. The text is tokenized chunk by chunk,
+// cutting chunks at white space, so the tokenizer must not produce words that
+// contain white space.
+func AnalyzeReader(r io.Reader, n int, opts StreamOptions) (Report, error) {
+	chunkSize := opts.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+
+	filter := newWordFilter(opts.Options)
+	tokenize := opts.tokenizer()
+	counts := newExactCounter(opts.MaxWords)
+	total := 0
+	count := func(chunk []byte) {
+		for _, token := range tokenize(opts.prepare(string(chunk))) {
+			word := opts.normalize(token)
+			if filter.accepts(word) {
+				counts.add(word)
+				total++
+			}
+		}
+	}
+
+	buf := make([]byte, 0, chunkSize)
+	for {
+		if len(buf) == cap(buf) {
+			// A single word fills the whole buffer, let it grow.
+			buf = append(buf, 0)[:len(buf)]
+		}
+		read, err := r.Read(buf[len(buf):cap(buf)])
+		buf = buf[:len(buf)+read]
+
+		if errors.Is(err, io.EOF) {
+			count(buf)
+			break
+		}
+		if err != nil {
+			return Report{}, err
+		}
+
+		// Everything before the last white space is complete words.
+		if cut := bytes.LastIndexFunc(buf, unicode.IsSpace); cut >= 0 {
+			count(buf[:cut])
+			buf = buf[:copy(buf, buf[cut:])]
+		}
+	}
+
+	wordFrequencies := counts.frequencies()
+	sortFrequencies(wordFrequencies)
+	return Report{
+		Words:       topFrequencies(wordFrequencies, n),
+		Total:       total,
+		Distinct:    len(wordFrequencies),
+		Approximate: counts.sketch != nil,
+		MaxError:    counts.maxError(),
+	}, nil
+}
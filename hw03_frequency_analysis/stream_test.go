@@ -0,0 +1,88 @@
+package hw03frequencyanalysis
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	//nolint:depguard
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeReader(t *testing.T) {
+	t.Run("same as analyze", func(t *testing.T) {
+		for _, chunkSize := range []int{1, 2, 7, 64, DefaultChunkSize} {
+			chunkSize := chunkSize
+			t.Run(fmt.Sprint(chunkSize), func(t *testing.T) {
+				opts := StreamOptions{ChunkSize: chunkSize}
+				report, err := AnalyzeReader(iotest.HalfReader(strings.NewReader(text)), 10, opts)
+				require.NoError(t, err)
+				require.Equal(t, Analyze(text, 10, Options{}), report)
+			})
+		}
+	})
+
+	t.Run("words split across chunks", func(t *testing.T) {
+		input := strings.Repeat("кристофер робин ", 100)
+		report, err := AnalyzeReader(iotest.OneByteReader(strings.NewReader(input)), 10, StreamOptions{ChunkSize: 3})
+		require.NoError(t, err)
+		require.Equal(t, []WordFrequency{{"кристофер", 100}, {"робин", 100}}, report.Words)
+	})
+
+	t.Run("options", func(t *testing.T) {
+		opts := StreamOptions{Options: Options{StopWords: []string{"а", "он"}, MinLength: 2}}
+		report, err := AnalyzeReader(strings.NewReader(text), 5, opts)
+		require.NoError(t, err)
+		require.Equal(t, Analyze(text, 5, opts.Options), report)
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		_, err := AnalyzeReader(iotest.ErrReader(iotest.ErrTimeout), 10, StreamOptions{})
+		require.ErrorIs(t, err, iotest.ErrTimeout)
+	})
+
+	t.Run("budget not exceeded", func(t *testing.T) {
+		report, err := AnalyzeReader(strings.NewReader(text), 10, StreamOptions{MaxWords: 1000})
+		require.NoError(t, err)
+		require.False(t, report.Approximate)
+		require.Zero(t, report.MaxError)
+	})
+
+	t.Run("heavy hitters", func(t *testing.T) {
+		var sb strings.Builder
+		for i := 0; i < 2000; i++ {
+			fmt.Fprintf(&sb, "hot warm hot cold%d ", i)
+			if i%2 == 0 {
+				sb.WriteString("warm ")
+			}
+		}
+
+		report, err := AnalyzeReader(strings.NewReader(sb.String()), 2, StreamOptions{
+			Options:  Options{Tokenizer: LettersDigitsTokenizer},
+			MaxWords: 50,
+		})
+		require.NoError(t, err)
+		require.True(t, report.Approximate)
+		require.Equal(t, 9000, report.Total)
+		require.LessOrEqual(t, report.MaxError, report.Total/50)
+
+		require.Equal(t, "hot", report.Words[0].Word)
+		require.Equal(t, "warm", report.Words[1].Word)
+		require.GreaterOrEqual(t, report.Words[0].Frequency, 4000)
+		require.LessOrEqual(t, report.Words[0].Frequency, 4000+report.MaxError)
+		require.GreaterOrEqual(t, report.Words[1].Frequency, 3000)
+		require.LessOrEqual(t, report.Words[1].Frequency, 3000+report.MaxError)
+	})
+}
+
+func TestSpaceSaving(t *testing.T) {
+	s := newSpaceSaving(map[string]int{"a": 3, "b": 1})
+	s.add("c")
+	s.add("a")
+	s.add("d")
+
+	// "c" replaced "b" inheriting its count, then "d" replaced "c".
+	require.ElementsMatch(t, []WordFrequency{{"a", 4}, {"d", 3}}, s.frequencies())
+	require.Equal(t, 3, s.maxError())
+}
@@ -1,11 +1,6 @@
 package hw03frequencyanalysis
 
-import (
-	"fmt"
-	"regexp"
-	"sort"
-	"strings"
-)
+import "sort"
 
 type WordFrequency struct {
 	Word      string
@@ -13,50 +8,104 @@ type WordFrequency struct {
 }
 
 func Top10(text string) []string {
-	// Place your code here.
-	words, errorText := processWords(text)
-	if errorText != nil {
-		return []string{}
+	return TopN(text, 10, Options{})
+}
+
+// Report is the result of Analyze.
+type Report struct {
+	// Words holds the most frequent words with their counts.
+	Words []WordFrequency
+	// Total is the number of counted words in the text, repeats included.
+	Total int
+	// Distinct is the number of different counted words. For an approximate
+	// report it is the number of words the sketch still tracks.
+	Distinct int
+	// Approximate is set when AnalyzeReader ran out of its word budget and
+	// switched to the heavy hitters sketch.
+	Approximate bool
+	// MaxError bounds how much an approximate frequency exceeds the real one.
+	MaxError int
+}
+
+// Share returns the fraction of all counted words taken by wf.
+func (r Report) Share(wf WordFrequency) float64 {
+	if r.Total == 0 {
+		return 0
 	}
+	return float64(wf.Frequency) / float64(r.Total)
+}
 
-	wordCount := make(map[string]int)
+// TopN returns the n most frequent words of text, ordered by frequency and
+// then lexicographically.
+func TopN(text string, n int, opts Options) []string {
+	report := Analyze(text, n, opts)
+	top := make([]string, 0, len(report.Words))
+	for _, wordFrequency := range report.Words {
+		top = append(top, wordFrequency.Word)
+	}
+	return top
+}
 
-	for _, word := range words {
-		if word != "-" {
-			wordCount[word]++
+// Analyze returns the n most frequent words of text with their counts, in the
+// TopN order, together with totals over the whole text.
+func Analyze(text string, n int, opts Options) Report {
+	words := processWords(text, opts)
+	wordFrequencies := countWords(words)
+	sortFrequencies(wordFrequencies)
+
+	report := Report{Total: len(words), Distinct: len(wordFrequencies)}
+	report.Words = topFrequencies(wordFrequencies, n)
+	return report
+}
+
+func topFrequencies(wordFrequencies []WordFrequency, n int) []WordFrequency {
+	if n < 0 {
+		n = 0
+	}
+	if n > len(wordFrequencies) {
+		n = len(wordFrequencies)
+	}
+	return wordFrequencies[:n:n]
+}
+
+func processWords(text string, opts Options) []string {
+	filter := newWordFilter(opts)
+	tokens := opts.tokenizer()(opts.prepare(text))
+	words := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		word := opts.normalize(token)
+		if filter.accepts(word) {
+			words = append(words, word)
 		}
 	}
+	return words
+}
 
+func countWords(words []string) []WordFrequency {
+	return frequencies(countMap(words))
+}
+
+func frequencies(wordCount map[string]int) []WordFrequency {
 	wordFrequencies := make([]WordFrequency, 0, len(wordCount))
 	for word, count := range wordCount {
 		wordFrequencies = append(wordFrequencies, WordFrequency{word, count})
 	}
+	return wordFrequencies
+}
+
+func countMap(words []string) map[string]int {
+	wordCount := make(map[string]int)
+	for _, word := range words {
+		wordCount[word]++
+	}
+	return wordCount
+}
 
+func sortFrequencies(wordFrequencies []WordFrequency) {
 	sort.Slice(wordFrequencies, func(i, j int) bool {
 		if wordFrequencies[i].Frequency == wordFrequencies[j].Frequency {
 			return wordFrequencies[i].Word < wordFrequencies[j].Word
 		}
 		return wordFrequencies[i].Frequency > wordFrequencies[j].Frequency
 	})
-
-	top10 := make([]string, 0)
-
-	for _, wordFrequency := range wordFrequencies {
-		if len(top10) == 10 {
-			break
-		}
-		top10 = append(top10, wordFrequency.Word)
-	}
-
-	return top10
-}
-
-func processWords(text string) ([]string, error) {
-	lowerText := strings.ToLower(text)
-	re := regexp.MustCompile(`[\p{L}-]+`)
-	words := re.FindAllString(lowerText, -1)
-	if len(words) == 0 {
-		return nil, fmt.Errorf("incorrect text")
-	}
-	return words, nil
 }
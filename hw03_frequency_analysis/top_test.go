@@ -1,6 +1,7 @@
 package hw03frequencyanalysis
 
 import (
+	"strings"
 	"testing"
 
 	//nolint:depguard
@@ -107,3 +108,79 @@ func TestTop10(t *testing.T) {
 		}
 	})
 }
+
+func TestTopN(t *testing.T) {
+	t.Run("top10 is top n with defaults", func(t *testing.T) {
+		require.Equal(t, Top10(text), TopN(text, 10, Options{}))
+	})
+
+	t.Run("n", func(t *testing.T) {
+		require.Equal(t, []string{"а", "он", "и"}, TopN(text, 3, Options{}))
+		require.Len(t, TopN(text, 1000, Options{}), 180)
+		require.Len(t, TopN(text, 0, Options{}), 0)
+		require.Len(t, TopN(text, -1, Options{}), 0)
+	})
+
+	t.Run("tokenizers", func(t *testing.T) {
+		input := "Go1 go1 go1, go - go-go 2024"
+
+		require.Equal(t, []string{"go", "go-go"}, TopN(input, 10, Options{}))
+		require.Equal(t,
+			[]string{"go1", "2024", "go", "go-go"},
+			TopN(input, 10, Options{Tokenizer: LettersDigitsTokenizer}))
+		require.Equal(t,
+			[]string{"go1", "2024", "go", "go-go", "go1,"},
+			TopN(input, 10, Options{Tokenizer: WhitespaceTokenizer}))
+	})
+
+	t.Run("custom tokenizer", func(t *testing.T) {
+		bySemicolon := func(text string) []string { return strings.Split(text, ";") }
+		require.Equal(t, []string{"a b", "c"}, TopN("a b;c;a b", 10, Options{Tokenizer: bySemicolon}))
+	})
+
+	t.Run("stop words", func(t *testing.T) {
+		opts := Options{StopWords: []string{"А", "он", "и"}}
+		require.Equal(t, []string{"ты", "что", "в"}, TopN(text, 3, opts))
+	})
+
+	t.Run("case", func(t *testing.T) {
+		input := "Нога нога НОГА"
+
+		require.Equal(t, []string{"нога"}, TopN(input, 10, Options{}))
+		require.Equal(t, []string{"НОГА", "Нога", "нога"}, TopN(input, 10, Options{Case: CaseKeep}))
+		require.Equal(t, []string{"σασ", "нога"}, TopN("нога ΣΑΣ σας", 10, Options{Case: CaseFold}))
+	})
+
+	t.Run("min length", func(t *testing.T) {
+		expected := []string{"если", "кристофер", "робин", "винни-пух", "иногда"}
+		require.Equal(t, expected, TopN(text, 5, Options{MinLength: 4}))
+	})
+}
+
+func TestAnalyze(t *testing.T) {
+	t.Run("counts", func(t *testing.T) {
+		report := Analyze("cat and dog, one dog, two cats and one man - and", 3, Options{})
+
+		require.Equal(t, []WordFrequency{{"and", 3}, {"dog", 2}, {"one", 2}}, report.Words)
+		require.Equal(t, 11, report.Total)
+		require.Equal(t, 7, report.Distinct)
+		require.InDelta(t, 3.0/11, report.Share(report.Words[0]), 1e-9)
+	})
+
+	t.Run("same order as top n", func(t *testing.T) {
+		report := Analyze(text, 10, Options{})
+		require.Len(t, report.Words, 10)
+		for i, word := range Top10(text) {
+			require.Equal(t, word, report.Words[i].Word)
+		}
+		require.Equal(t, WordFrequency{"а", 8}, report.Words[0])
+		require.Equal(t, 180, report.Distinct)
+	})
+
+	t.Run("empty text", func(t *testing.T) {
+		report := Analyze(" \t\n", 10, Options{})
+		require.Empty(t, report.Words)
+		require.Zero(t, report.Total)
+		require.Zero(t, report.Share(WordFrequency{"a", 1}))
+	})
+}
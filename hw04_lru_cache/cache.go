@@ -1,75 +1,418 @@
 package hw04lrucache
 
-import "sync"
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+var (
+	ErrTooLarge     = errors.New("item cost exceeds the cache budget")
+	ErrNegativeCost = errors.New("item cost is negative")
+)
 
 type Key string
 
+// Cache is the LRU cache of arbitrary values.
 type Cache interface {
 	Set(key Key, value interface{}) bool
+	SetWithTTL(key Key, value interface{}, ttl time.Duration) bool
+	SetWithCost(key Key, value interface{}, cost int64) (bool, error)
 	Get(key Key) (interface{}, bool)
+	Peek(key Key) (interface{}, bool)
+	Contains(key Key) bool
+	Delete(key Key) bool
+	Len() int
+	Keys() []Key
+	Resize(capacity int) int
+	Clear()
+	Stats() Stats
+	Snapshot(w io.Writer) error
+	Restore(r io.Reader) error
+	Close()
+}
+
+// TypedCache is the LRU cache with keys of type K and values of type V.
+type TypedCache[K comparable, V any] interface {
+	Set(key K, value V) bool
+	SetWithTTL(key K, value V, ttl time.Duration) bool
+	SetWithCost(key K, value V, cost int64) (bool, error)
+	Get(key K) (V, bool)
+	Peek(key K) (V, bool)
+	Contains(key K) bool
+	Delete(key K) bool
+	Len() int
+	Keys() []K
+	Resize(capacity int) int
 	Clear()
+	Stats() Stats
+	Snapshot(w io.Writer) error
+	Restore(r io.Reader) error
+	Close()
 }
 
-type lruCache struct {
+type lruCache[K comparable, V any] struct {
 	mu       sync.Mutex
 	capacity int
-	queue    List
-	items    map[Key]*ListItem
+	maxCost  int64
+	cost     int64
+	sizer    func(key K, value V) int64
+	ttl      time.Duration
+	now      func() time.Time
+	onEvict  func(key K, value V, reason EvictReason)
+	policy   evictionPolicy[K, V]
+	items    map[K]*TypedListItem[cacheElement[K, V]]
+	// janitor is closed when the janitor goroutine stopped by stopJanitor exits.
+	janitor     chan struct{}
+	stopJanitor context.CancelFunc
+	counters    counters
+	codec       Codec
 }
 
-type cacheElement struct {
-	Key Key
-	Val interface{}
+type cacheElement[K comparable, V any] struct {
+	Key K
+	Val V
+	// ExpiresAt is zero for entries that never expire.
+	ExpiresAt time.Time
+	Cost      int64
+	// hits and segment belong to the eviction policy.
+	hits    int
+	segment segment
 }
 
-func (lru *lruCache) Set(key Key, value interface{}) bool {
+func (e cacheElement[K, V]) expired(now time.Time) bool {
+	return !e.ExpiresAt.IsZero() && !now.Before(e.ExpiresAt)
+}
+
+// Set adds the value with the default TTL. With a cost budget, a value the
+// sizer finds larger than the whole budget is not stored and the old value
+// of the key is removed, as is a value of negative cost.
+func (lru *lruCache[K, V]) Set(key K, value V) bool {
+	wasInCache, _ := lru.set(key, value, lru.ttl, lru.costOf(key, value))
+	return wasInCache
+}
+
+// SetWithTTL adds the value that expires after ttl, a non-positive ttl means never.
+func (lru *lruCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) bool {
+	wasInCache, _ := lru.set(key, value, ttl, lru.costOf(key, value))
+	return wasInCache
+}
+
+// SetWithCost adds the value with the given cost instead of the one from the
+// sizer. When the cost exceeds the whole budget it returns ErrTooLarge, when
+// it is negative ErrNegativeCost. Then the value is not stored and the old
+// value of the key is removed, so that it does not outlive the rejected one.
+func (lru *lruCache[K, V]) SetWithCost(key K, value V, cost int64) (bool, error) {
+	return lru.set(key, value, lru.ttl, cost)
+}
+
+func (lru *lruCache[K, V]) set(key K, value V, ttl time.Duration, cost int64) (bool, error) {
+	var evicted evictions[K, V]
+	defer func() { lru.notify(evicted) }()
+
 	lru.mu.Lock()
 	defer lru.mu.Unlock()
 
-	newCacheElement := cacheElement{Key: key, Val: value}
-	if item, found := lru.items[key]; found {
-		item.Value = newCacheElement
-		lru.queue.MoveToFront(item)
-		return true
+	now := lru.now()
+	if err := lru.checkCost(cost); err != nil {
+		wasInCache := false
+		if item, found := lru.items[key]; found {
+			wasInCache = !item.Value.expired(now)
+			lru.remove(item, EvictCapacity, &evicted)
+		}
+		return wasInCache, err
 	}
 
-	if lru.queue.Len() == lru.capacity {
-		lastItem := lru.queue.Back()
-		if lastItem != nil {
-			delete(lru.items, lastItem.Value.(cacheElement).Key)
-			lru.queue.Remove(lastItem)
+	newCacheElement := cacheElement[K, V]{Key: key, Val: value, Cost: cost}
+	if ttl > 0 {
+		newCacheElement.ExpiresAt = now.Add(ttl)
+	}
+	return lru.put(newCacheElement, now, &evicted), nil
+}
+
+// put adds the element or updates the one with its key, making it the most
+// recently used. It is called with lru.mu held.
+func (lru *lruCache[K, V]) put(elem cacheElement[K, V], now time.Time, evicted *evictions[K, V]) bool {
+	if item, found := lru.items[elem.Key]; found {
+		wasInCache := !item.Value.expired(now)
+		if wasInCache {
+			lru.counters.overwrites.Add(1)
+		}
+		lru.cost += elem.Cost - item.Value.Cost
+		item.Value.Val, item.Value.ExpiresAt, item.Value.Cost = elem.Val, elem.ExpiresAt, elem.Cost
+		if lru.maxCost > 0 && lru.cost > lru.maxCost {
+			// Take the entry out while making room, so that the policy
+			// does not evict it for itself.
+			lru.policy.Remove(item, EvictDeleted)
+			lru.cost -= elem.Cost
+			lru.evictOverBudget(elem.Cost, evicted)
+			lru.policy.Reinsert(item)
+			lru.cost += elem.Cost
 		}
+		lru.policy.Touch(item)
+		return wasInCache
 	}
 
-	newItem := lru.queue.PushFront(newCacheElement)
-	lru.items[key] = newItem
+	if lru.capacity > 0 && lru.policy.Len() >= lru.capacity {
+		if victim := lru.policy.Victim(); victim != nil {
+			lru.remove(victim, EvictCapacity, evicted)
+		}
+	}
+	lru.evictOverBudget(elem.Cost, evicted)
+
+	newItem := lru.policy.Push(elem)
+	lru.items[elem.Key] = newItem
+	lru.cost += elem.Cost
 
 	return false
 }
 
-func (lru *lruCache) Get(key Key) (interface{}, bool) {
+func (lru *lruCache[K, V]) checkCost(cost int64) error {
+	switch {
+	case cost < 0:
+		return ErrNegativeCost
+	case lru.maxCost > 0 && cost > lru.maxCost:
+		return ErrTooLarge
+	}
+	return nil
+}
+
+func (lru *lruCache[K, V]) costOf(key K, value V) int64 {
+	if lru.sizer != nil {
+		return lru.sizer(key, value)
+	}
+	return 1
+}
+
+// evictOverBudget removes the entries chosen by the policy until extra more
+// cost fits into the budget.
+func (lru *lruCache[K, V]) evictOverBudget(extra int64, evicted *evictions[K, V]) {
+	if lru.maxCost <= 0 {
+		return
+	}
+	for lru.cost+extra > lru.maxCost && lru.policy.Len() > 0 {
+		lru.remove(lru.policy.Victim(), EvictCapacity, evicted)
+	}
+}
+
+func (lru *lruCache[K, V]) Get(key K) (V, bool) {
+	var evicted evictions[K, V]
+	defer func() { lru.notify(evicted) }()
+
 	lru.mu.Lock()
 	defer lru.mu.Unlock()
 
 	if item, found := lru.items[key]; found {
-		lru.queue.MoveToFront(item)
-		return item.Value.(cacheElement).Val, true
+		if item.Value.expired(lru.now()) {
+			lru.remove(item, EvictExpired, &evicted)
+		} else {
+			lru.policy.Touch(item)
+			lru.counters.hits.Add(1)
+			return item.Value.Val, true
+		}
 	}
-	return nil, false
+	lru.counters.misses.Add(1)
+	var zero V
+	return zero, false
 }
 
-func (lru *lruCache) Clear() {
+// Peek returns the value like Get, but does not make it the most recently used.
+func (lru *lruCache[K, V]) Peek(key K) (V, bool) {
 	lru.mu.Lock()
 	defer lru.mu.Unlock()
 
-	lru.queue = NewList()
-	lru.items = make(map[Key]*ListItem, lru.capacity)
+	if item, found := lru.items[key]; found && !item.Value.expired(lru.now()) {
+		return item.Value.Val, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Contains reports whether the key is in the cache without touching its recency.
+func (lru *lruCache[K, V]) Contains(key K) bool {
+	_, found := lru.Peek(key)
+	return found
 }
 
-func NewCache(capacity int) Cache {
-	return &lruCache{
+// Delete removes the key and reports whether it was in the cache.
+func (lru *lruCache[K, V]) Delete(key K) bool {
+	var evicted evictions[K, V]
+	defer func() { lru.notify(evicted) }()
+
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	item, found := lru.items[key]
+	if !found {
+		return false
+	}
+	if item.Value.expired(lru.now()) {
+		lru.remove(item, EvictExpired, &evicted)
+		return false
+	}
+	lru.remove(item, EvictDeleted, &evicted)
+	return true
+}
+
+// Len returns the number of entries, counting expired ones not removed yet.
+func (lru *lruCache[K, V]) Len() int {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	return lru.policy.Len()
+}
+
+// Keys returns the keys from the most to the least recently used, or in the
+// order of the eviction policy, most valuable first.
+func (lru *lruCache[K, V]) Keys() []K {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	now := lru.now()
+	items := lru.policy.Items()
+	keys := make([]K, 0, len(items))
+	for _, item := range items {
+		if !item.Value.expired(now) {
+			keys = append(keys, item.Value.Key)
+		}
+	}
+	return keys
+}
+
+// Resize changes the capacity, evicting the least recently used entries
+// that do not fit, and returns how many were evicted. Non-positive
+// capacities are ignored.
+func (lru *lruCache[K, V]) Resize(capacity int) int {
+	var evicted evictions[K, V]
+	defer func() { lru.notify(evicted) }()
+
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	if capacity <= 0 {
+		return 0
+	}
+	lru.capacity = capacity
+	lru.policy.Resize(capacity)
+	removed := 0
+	for lru.policy.Len() > capacity {
+		lru.remove(lru.policy.Victim(), EvictCapacity, &evicted)
+		removed++
+	}
+	return removed
+}
+
+func (lru *lruCache[K, V]) Clear() {
+	var evicted evictions[K, V]
+	defer func() { lru.notify(evicted) }()
+
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	if lru.onEvict != nil {
+		items := lru.policy.Items()
+		for i := len(items) - 1; i >= 0; i-- {
+			evicted = append(evicted, eviction[K, V]{items[i].Value, EvictCleared})
+		}
+	}
+	lru.policy.Reset()
+	lru.items = make(map[K]*TypedListItem[cacheElement[K, V]], max(lru.capacity, 0))
+	lru.cost = 0
+}
+
+// remove drops the item, recording it in evicted when there is an eviction hook.
+func (lru *lruCache[K, V]) remove(
+	item *TypedListItem[cacheElement[K, V]], reason EvictReason, evicted *evictions[K, V],
+) {
+	delete(lru.items, item.Value.Key)
+	lru.policy.Remove(item, reason)
+	lru.cost -= item.Value.Cost
+	lru.counters.removed(reason)
+	if lru.onEvict != nil {
+		*evicted = append(*evicted, eviction[K, V]{item.Value, reason})
+	}
+}
+
+// removeExpired drops every expired entry.
+func (lru *lruCache[K, V]) removeExpired() {
+	var evicted evictions[K, V]
+	defer func() { lru.notify(evicted) }()
+
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	now := lru.now()
+	items := lru.policy.Items()
+	for i := len(items) - 1; i >= 0; i-- {
+		if items[i].Value.expired(now) {
+			lru.remove(items[i], EvictExpired, &evicted)
+		}
+	}
+}
+
+func (lru *lruCache[K, V]) runJanitor(ctx context.Context, interval time.Duration) {
+	defer close(lru.janitor)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			lru.removeExpired()
+		}
+	}
+}
+
+// NewCache creates the cache holding at most capacity entries, a non-positive
+// capacity means no limit on the number of entries.
+func NewCache(capacity int, opts ...TypedOption[Key, interface{}]) Cache {
+	return newLRUCache[Key, interface{}](capacity, opts...)
+}
+
+func NewTypedCache[K comparable, V any](capacity int, opts ...TypedOption[K, V]) TypedCache[K, V] {
+	return newLRUCache[K, V](capacity, opts...)
+}
+
+func newLRUCache[K comparable, V any](capacity int, opts ...TypedOption[K, V]) *lruCache[K, V] {
+	return newLRUCacheWithOptions[K, V](capacity, buildOptions(opts))
+}
+
+func newLRUCacheWithOptions[K comparable, V any](capacity int, o options) *lruCache[K, V] {
+	lru := &lruCache[K, V]{
 		capacity: capacity,
-		queue:    NewList(),
-		items:    make(map[Key]*ListItem, capacity),
+		maxCost:  o.maxCost,
+		ttl:      o.ttl,
+		now:      o.now,
+		codec:    o.codec,
+		policy:   newEvictionPolicy[K, V](o.policy, capacity),
+		items:    make(map[K]*TypedListItem[cacheElement[K, V]], max(capacity, 0)),
+	}
+	// TypedOption makes sure that the sizer and the hook match the cache types.
+	if o.sizer != nil {
+		lru.sizer = o.sizer.(func(key K, value V) int64)
+	}
+	if o.onEvict != nil {
+		lru.onEvict = o.onEvict.(func(key K, value V, reason EvictReason))
+	}
+	if o.janitorCtx != nil && o.janitorInterval > 0 {
+		ctx, cancel := context.WithCancel(o.janitorCtx)
+		lru.janitor = make(chan struct{})
+		lru.stopJanitor = cancel
+		go lru.runJanitor(ctx, o.janitorInterval)
+	}
+	return lru
+}
+
+// Close stops the janitor started by WithJanitor and waits for it to exit.
+// The cache stays usable, expired entries are then only dropped lazily.
+func (lru *lruCache[K, V]) Close() {
+	if lru.janitor == nil {
+		return
 	}
+	lru.stopJanitor()
+	<-lru.janitor
 }
@@ -1,10 +1,13 @@
 package hw04lrucache
 
 import (
+	"context"
 	"math/rand"
 	"strconv"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	//nolint:depguard
 	"github.com/stretchr/testify/require"
@@ -201,3 +204,399 @@ func TestCacheMultithreading(_ *testing.T) {
 
 	wg.Wait()
 }
+
+func TestTypedCache(t *testing.T) {
+	t.Run("purge logic", func(t *testing.T) {
+		c := NewTypedCache[int, string](2)
+
+		require.False(t, c.Set(1, "one"))
+		require.False(t, c.Set(2, "two"))
+
+		val, ok := c.Get(1)
+		require.True(t, ok)
+		require.Equal(t, "one", val)
+
+		require.False(t, c.Set(3, "three"))
+
+		val, ok = c.Get(2)
+		require.False(t, ok)
+		require.Equal(t, "", val)
+
+		require.True(t, c.Set(1, "uno"))
+		val, ok = c.Get(1)
+		require.True(t, ok)
+		require.Equal(t, "uno", val)
+	})
+
+	t.Run("struct values", func(t *testing.T) {
+		type point struct{ X, Y int }
+		c := NewTypedCache[string, point](3)
+
+		c.Set("a", point{1, 2})
+		val, ok := c.Get("a")
+		require.True(t, ok)
+		require.Equal(t, point{1, 2}, val)
+
+		c.Clear()
+		_, ok = c.Get("a")
+		require.False(t, ok)
+	})
+
+	t.Run("nil values are cached", func(t *testing.T) {
+		c := NewCache(3)
+
+		require.False(t, c.Set("nil", nil))
+		val, ok := c.Get("nil")
+		require.True(t, ok)
+		require.Nil(t, val)
+	})
+}
+
+func BenchmarkCache(b *testing.B) {
+	const capacity = 1024
+	keys := make([]Key, 4*capacity)
+	for i := range keys {
+		keys[i] = Key(strconv.Itoa(i))
+	}
+
+	b.Run("interface", func(b *testing.B) {
+		c := NewCache(capacity)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			key := keys[i%len(keys)]
+			if _, ok := c.Get(key); !ok {
+				c.Set(key, i)
+			}
+		}
+	})
+
+	b.Run("typed", func(b *testing.B) {
+		c := NewTypedCache[Key, int](capacity)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			key := keys[i%len(keys)]
+			if _, ok := c.Get(key); !ok {
+				c.Set(key, i)
+			}
+		}
+	})
+}
+
+// fakeClock is a manually advanced time source for TTL tests.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestCache(capacity int, opts ...TypedOption[Key, interface{}]) (*lruCache[Key, interface{}], *fakeClock) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	opts = append(opts, func(o *options) { o.now = clock.Now })
+	return newLRUCache[Key, interface{}](capacity, opts...), clock
+}
+
+func TestCacheTTL(t *testing.T) {
+	t.Run("set with ttl", func(t *testing.T) {
+		c, clock := newTestCache(3)
+
+		c.SetWithTTL("short", 1, time.Second)
+		c.SetWithTTL("long", 2, time.Minute)
+		c.Set("forever", 3)
+
+		clock.Advance(time.Second)
+
+		_, ok := c.Get("short")
+		require.False(t, ok)
+		require.Equal(t, 2, c.policy.Len(), "expired entry is removed on Get")
+
+		val, ok := c.Get("long")
+		require.True(t, ok)
+		require.Equal(t, 2, val)
+
+		clock.Advance(24 * time.Hour)
+
+		_, ok = c.Get("long")
+		require.False(t, ok)
+
+		val, ok = c.Get("forever")
+		require.True(t, ok)
+		require.Equal(t, 3, val)
+	})
+
+	t.Run("default ttl", func(t *testing.T) {
+		c, clock := newTestCache(3, WithTTL(time.Minute))
+
+		c.Set("default", 1)
+		c.SetWithTTL("custom", 2, time.Hour)
+		c.SetWithTTL("forever", 3, 0)
+
+		clock.Advance(time.Minute)
+
+		_, ok := c.Get("default")
+		require.False(t, ok)
+		_, ok = c.Get("custom")
+		require.True(t, ok)
+		_, ok = c.Get("forever")
+		require.True(t, ok)
+	})
+
+	t.Run("set over expired entry", func(t *testing.T) {
+		c, clock := newTestCache(3)
+
+		require.False(t, c.SetWithTTL("key", 1, time.Second))
+		clock.Advance(time.Second)
+
+		require.False(t, c.Set("key", 2), "expired entry was not in cache")
+		val, ok := c.Get("key")
+		require.True(t, ok)
+		require.Equal(t, 2, val)
+	})
+
+	t.Run("janitor", func(t *testing.T) {
+		c, clock := newTestCache(3, WithJanitor(context.Background(), time.Millisecond))
+
+		c.SetWithTTL("key", 1, time.Second)
+		c.Set("forever", 2)
+		clock.Advance(time.Second)
+
+		require.Eventually(t, func() bool {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			return c.policy.Len() == 1
+		}, time.Second, time.Millisecond)
+
+		c.Close()
+		select {
+		case <-c.janitor:
+		default:
+			t.Fatal("Close returned before the janitor stopped")
+		}
+		c.Close()
+	})
+
+	t.Run("janitor stops with its context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		c := NewShardedCache(2, 4, WithJanitor(ctx, time.Millisecond))
+		cancel()
+		c.Close()
+	})
+
+	t.Run("no janitor by default", func(t *testing.T) {
+		c, _ := newTestCache(3)
+		require.Nil(t, c.janitor)
+		c.Close()
+	})
+}
+
+func TestCacheOperations(t *testing.T) {
+	t.Run("peek does not touch recency", func(t *testing.T) {
+		c := NewCache(2)
+		c.Set("a", 1)
+		c.Set("b", 2)
+
+		val, ok := c.Peek("a")
+		require.True(t, ok)
+		require.Equal(t, 1, val)
+		require.True(t, c.Contains("a"))
+
+		c.Set("c", 3)
+		require.False(t, c.Contains("a"), "peeked entry is still the least recently used")
+		_, ok = c.Peek("a")
+		require.False(t, ok)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		rec := &evictRecorder{}
+		c := NewCache(3, WithOnEvict(rec.hook))
+		c.Set("a", 1)
+		c.Set("b", 2)
+
+		require.True(t, c.Delete("a"))
+		require.False(t, c.Delete("a"))
+		require.False(t, c.Contains("a"))
+		require.Equal(t, 1, c.Len())
+		require.Equal(t, []evictedEntry{{"a", 1, EvictDeleted}}, rec.get())
+	})
+
+	t.Run("keys in recency order", func(t *testing.T) {
+		c := NewCache(3)
+		require.Empty(t, c.Keys())
+
+		c.Set("a", 1)
+		c.Set("b", 2)
+		c.Set("c", 3)
+		c.Get("a")
+
+		require.Equal(t, []Key{"a", "c", "b"}, c.Keys())
+		require.Equal(t, 3, c.Len())
+	})
+
+	t.Run("expired entries", func(t *testing.T) {
+		c, clock := newTestCache(3)
+		c.SetWithTTL("a", 1, time.Second)
+		c.Set("b", 2)
+		clock.Advance(time.Second)
+
+		require.False(t, c.Contains("a"))
+		require.Equal(t, []Key{"b"}, c.Keys())
+		require.Equal(t, 2, c.Len(), "expired entry is not removed yet")
+		require.False(t, c.Delete("a"))
+		require.Equal(t, 1, c.Len())
+	})
+
+	t.Run("resize", func(t *testing.T) {
+		rec := &evictRecorder{}
+		c := NewCache(4, WithOnEvict(rec.hook))
+		for i, key := range []Key{"a", "b", "c", "d"} {
+			c.Set(key, i)
+		}
+
+		require.Equal(t, 0, c.Resize(0))
+		require.Equal(t, 2, c.Resize(2))
+		require.Equal(t, []Key{"d", "c"}, c.Keys())
+		require.Equal(t, []evictedEntry{{"a", 0, EvictCapacity}, {"b", 1, EvictCapacity}}, rec.get())
+
+		c.Set("e", 4)
+		require.Equal(t, []Key{"e", "d"}, c.Keys())
+
+		require.Equal(t, 0, c.Resize(3))
+		c.Set("f", 5)
+		require.Equal(t, []Key{"f", "e", "d"}, c.Keys())
+	})
+}
+
+func TestCacheWeighted(t *testing.T) {
+	t.Run("evicts until cost fits", func(t *testing.T) {
+		rec := &evictRecorder{}
+		c := NewCache(0, WithMaxCost(10), WithOnEvict(rec.hook))
+
+		for _, key := range []Key{"a", "b", "c"} {
+			_, err := c.SetWithCost(key, string(key), 3)
+			require.NoError(t, err)
+		}
+		c.Get("a")
+
+		_, err := c.SetWithCost("d", "d", 5)
+		require.NoError(t, err)
+		require.Equal(t, []Key{"d", "a"}, c.Keys())
+		require.Equal(t, []evictedEntry{{"b", "b", EvictCapacity}, {"c", "c", EvictCapacity}}, rec.get())
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		c := NewCache(0, WithMaxCost(10))
+		c.Set("a", 1)
+
+		wasInCache, err := c.SetWithCost("b", 2, 11)
+		require.ErrorIs(t, err, ErrTooLarge)
+		require.False(t, wasInCache)
+		require.Equal(t, []Key{"a"}, c.Keys())
+
+		wasInCache, err = c.SetWithCost("a", 10, 11)
+		require.ErrorIs(t, err, ErrTooLarge)
+		require.True(t, wasInCache)
+		_, ok := c.Get("a")
+		require.False(t, ok, "the old value does not outlive the rejected one")
+	})
+
+	t.Run("too large for an existing key with sizer", func(t *testing.T) {
+		rec := &evictRecorder{}
+		c := NewCache(0,
+			WithMaxCost(10),
+			WithSizer(func(_ Key, value interface{}) int64 { return int64(len(value.(string))) }),
+			WithOnEvict(rec.hook),
+		)
+		c.Set("a", "xx")
+
+		require.True(t, c.Set("a", strings.Repeat("x", 20)))
+		_, ok := c.Get("a")
+		require.False(t, ok)
+		require.Equal(t, []evictedEntry{{"a", "xx", EvictCapacity}}, rec.get())
+		require.Equal(t, int64(0), c.Stats().Cost)
+	})
+
+	t.Run("negative cost", func(t *testing.T) {
+		c := NewCache(0, WithMaxCost(10))
+		c.SetWithCost("a", 1, 5)
+
+		wasInCache, err := c.SetWithCost("neg", 1, -100)
+		require.ErrorIs(t, err, ErrNegativeCost)
+		require.False(t, wasInCache)
+		require.False(t, c.Contains("neg"))
+
+		_, err = c.SetWithCost("a", 1, -1)
+		require.ErrorIs(t, err, ErrNegativeCost)
+		require.False(t, c.Contains("a"))
+
+		for i := 0; i < 20; i++ {
+			c.SetWithCost(Key(strconv.Itoa(i)), i, 1)
+		}
+		require.Equal(t, 10, c.Len())
+		require.Equal(t, int64(10), c.Stats().Cost)
+
+		sized := NewCache(0, WithMaxCost(10), WithSizer(func(Key, interface{}) int64 { return -1 }))
+		require.False(t, sized.Set("a", 1))
+		require.Equal(t, 0, sized.Len())
+	})
+
+	t.Run("overwrite changes cost", func(t *testing.T) {
+		c := NewCache(0, WithMaxCost(10))
+		c.SetWithCost("a", 1, 4)
+		c.SetWithCost("b", 2, 4)
+
+		wasInCache, err := c.SetWithCost("b", 20, 8)
+		require.NoError(t, err)
+		require.True(t, wasInCache)
+		require.Equal(t, []Key{"b"}, c.Keys())
+
+		c.SetWithCost("b", 2, 1)
+		c.SetWithCost("c", 3, 9)
+		require.Equal(t, []Key{"c", "b"}, c.Keys())
+	})
+
+	t.Run("entry and cost limits", func(t *testing.T) {
+		c := NewCache(2, WithMaxCost(100))
+		c.SetWithCost("a", 1, 1)
+		c.SetWithCost("b", 2, 1)
+		c.SetWithCost("c", 3, 1)
+		require.Equal(t, []Key{"c", "b"}, c.Keys())
+	})
+
+	t.Run("sizer", func(t *testing.T) {
+		c := NewTypedCache[string, []byte](0,
+			WithMaxCost(8),
+			WithSizer(func(_ string, value []byte) int64 { return int64(len(value)) }),
+		)
+
+		c.Set("a", make([]byte, 3))
+		c.Set("b", make([]byte, 3))
+		c.Set("c", make([]byte, 3))
+		require.Equal(t, []string{"c", "b"}, c.Keys())
+
+		require.False(t, c.Set("d", make([]byte, 9)))
+		require.False(t, c.Contains("d"))
+		require.Equal(t, 2, c.Len())
+	})
+
+	t.Run("delete and clear release cost", func(t *testing.T) {
+		c := NewCache(0, WithMaxCost(4))
+		c.SetWithCost("a", 1, 4)
+		c.Delete("a")
+		c.SetWithCost("b", 2, 4)
+		require.Equal(t, []Key{"b"}, c.Keys())
+
+		c.Clear()
+		c.SetWithCost("c", 3, 4)
+		require.Equal(t, []Key{"c"}, c.Keys())
+	})
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	hw04lrucache "github.com/Nickolas990/otus_hw/hw04_lru_cache"
+)
+
+const usage = `Usage: cachesim [-capacity 1000] [-policies lru,lfu,2q,arc] [file]
+
+Replays the trace of keys, one per line, read from the file or stdin against
+caches with every policy and prints their hit ratios.
+`
+
+type config struct {
+	capacity int
+	policies []hw04lrucache.Policy
+	file     string
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command line and returns the process exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	cfg, err := parseArgs(args, stderr)
+	if err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(stderr, err)
+		}
+		return 2
+	}
+
+	in := stdin
+	if cfg.file != "" {
+		f, err := os.Open(cfg.file)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		defer f.Close()
+		in = f
+	}
+
+	results, err := hw04lrucache.SimulateReader(in, cfg.capacity, cfg.policies...)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	if err := printResults(stdout, results); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func parseArgs(args []string, stderr io.Writer) (config, error) {
+	var cfg config
+	var policies string
+	fs := flag.NewFlagSet("cachesim", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		fs.PrintDefaults()
+	}
+	fs.IntVar(&cfg.capacity, "capacity", 1000, "number of entries in every cache")
+	fs.StringVar(&policies, "policies", "lru,lfu,2q,arc", "comma separated eviction policies")
+	if err := fs.Parse(args); err != nil {
+		return cfg, err
+	}
+
+	if cfg.capacity <= 0 {
+		return cfg, fmt.Errorf("capacity must be positive, got %d", cfg.capacity)
+	}
+	for _, name := range strings.Split(policies, ",") {
+		p, err := hw04lrucache.ParsePolicy(strings.TrimSpace(name))
+		if err != nil {
+			return cfg, err
+		}
+		cfg.policies = append(cfg.policies, p)
+	}
+	switch fs.NArg() {
+	case 0:
+	case 1:
+		cfg.file = fs.Arg(0)
+	default:
+		return cfg, errors.New("at most one trace file is allowed")
+	}
+	return cfg, nil
+}
+
+func printResults(w io.Writer, results []hw04lrucache.SimulationResult) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "policy\thits\tmisses\thit ratio")
+	for _, r := range results {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.4f\n", r.Policy, r.Hits, r.Misses, r.HitRatio())
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	//nolint:depguard
+	"github.com/stretchr/testify/require"
+)
+
+const trace = "a\nb\na\nc\na\nb\n"
+
+func runCommand(t *testing.T, stdin string, args ...string) (string, string, int) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return stdout.String(), stderr.String(), code
+}
+
+func TestRun(t *testing.T) {
+	t.Run("stdin", func(t *testing.T) {
+		stdout, stderr, code := runCommand(t, trace, "-capacity", "2", "-policies", "lru, lfu")
+		require.Equal(t, 0, code, stderr)
+		require.Equal(t, ""+
+			"policy  hits  misses  hit ratio\n"+
+			"lru     2     4       0.3333\n"+
+			"lfu     2     4       0.3333\n", stdout)
+	})
+
+	t.Run("file", func(t *testing.T) {
+		name := filepath.Join(t.TempDir(), "trace.txt")
+		require.NoError(t, os.WriteFile(name, []byte(trace), 0o600))
+
+		stdout, stderr, code := runCommand(t, "", "-capacity", "3", name)
+		require.Equal(t, 0, code, stderr)
+		require.Len(t, strings.Split(strings.TrimSpace(stdout), "\n"), 5)
+		require.Contains(t, stdout, "arc     3     3       0.5000")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, stderr, code := runCommand(t, trace, "-policies", "mru")
+		require.Equal(t, 2, code)
+		require.Contains(t, stderr, "unknown eviction policy")
+
+		_, stderr, code = runCommand(t, trace, "-capacity", "0")
+		require.Equal(t, 2, code)
+		require.Contains(t, stderr, "capacity must be positive")
+
+		_, _, code = runCommand(t, "", "a.txt", "b.txt")
+		require.Equal(t, 2, code)
+
+		_, _, code = runCommand(t, "", "missing.txt")
+		require.Equal(t, 1, code)
+	})
+}
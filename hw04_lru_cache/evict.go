@@ -0,0 +1,56 @@
+package hw04lrucache
+
+import "fmt"
+
+// EvictReason tells why an entry left the cache.
+type EvictReason int
+
+const (
+	// EvictCapacity is removal of the least recently used entry to make room.
+	EvictCapacity EvictReason = iota + 1
+	// EvictDeleted is removal by an explicit Delete.
+	EvictDeleted
+	// EvictExpired is removal of an entry whose TTL has passed.
+	EvictExpired
+	// EvictCleared is removal by Clear.
+	EvictCleared
+)
+
+func (r EvictReason) String() string {
+	switch r {
+	case EvictCapacity:
+		return "capacity"
+	case EvictDeleted:
+		return "deleted"
+	case EvictExpired:
+		return "expired"
+	case EvictCleared:
+		return "cleared"
+	default:
+		return fmt.Sprintf("EvictReason(%d)", int(r))
+	}
+}
+
+// WithOnEvict sets the hook called for every entry leaving the cache. The
+// hook runs after the cache lock is released, so it may use the cache. Its
+// key and value types are those of the cache, Key and interface{} for NewCache.
+func WithOnEvict[K comparable, V any](fn func(key K, value V, reason EvictReason)) TypedOption[K, V] {
+	return func(o *options) {
+		o.onEvict = fn
+	}
+}
+
+type eviction[K comparable, V any] struct {
+	elem   cacheElement[K, V]
+	reason EvictReason
+}
+
+// evictions collects the entries removed under the lock, so that the hook
+// can be called once the lock is released.
+type evictions[K comparable, V any] []eviction[K, V]
+
+func (lru *lruCache[K, V]) notify(evicted evictions[K, V]) {
+	for _, e := range evicted {
+		lru.onEvict(e.elem.Key, e.elem.Val, e.reason)
+	}
+}
@@ -0,0 +1,104 @@
+package hw04lrucache
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	//nolint:depguard
+	"github.com/stretchr/testify/require"
+)
+
+type evictedEntry struct {
+	key    Key
+	value  interface{}
+	reason EvictReason
+}
+
+// evictRecorder collects the calls of an eviction hook.
+type evictRecorder struct {
+	mu      sync.Mutex
+	entries []evictedEntry
+}
+
+func (r *evictRecorder) hook(key Key, value interface{}, reason EvictReason) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, evictedEntry{key, value, reason})
+}
+
+func (r *evictRecorder) get() []evictedEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]evictedEntry(nil), r.entries...)
+}
+
+func TestOnEvict(t *testing.T) {
+	t.Run("capacity", func(t *testing.T) {
+		rec := &evictRecorder{}
+		c := NewCache(2, WithOnEvict(rec.hook))
+
+		c.Set("a", 1)
+		c.Set("b", 2)
+		c.Get("a")
+		c.Set("b", 20)
+		require.Empty(t, rec.get(), "overwriting a value is not an eviction")
+
+		c.Set("c", 3)
+		require.Equal(t, []evictedEntry{{"a", 1, EvictCapacity}}, rec.get())
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		rec := &evictRecorder{}
+		c, clock := newTestCache(3, WithOnEvict(rec.hook))
+
+		c.SetWithTTL("a", 1, time.Second)
+		clock.Advance(time.Second)
+		_, ok := c.Get("a")
+		require.False(t, ok)
+
+		c.SetWithTTL("b", 2, time.Second)
+		c.SetWithTTL("c", 3, time.Second)
+		clock.Advance(time.Second)
+		c.removeExpired()
+
+		require.Equal(t, []evictedEntry{
+			{"a", 1, EvictExpired},
+			{"b", 2, EvictExpired},
+			{"c", 3, EvictExpired},
+		}, rec.get())
+	})
+
+	t.Run("clear", func(t *testing.T) {
+		rec := &evictRecorder{}
+		c := NewCache(3, WithOnEvict(rec.hook))
+
+		c.Set("a", 1)
+		c.Set("b", 2)
+		c.Clear()
+
+		require.Equal(t, []evictedEntry{{"a", 1, EvictCleared}, {"b", 2, EvictCleared}}, rec.get())
+	})
+
+	t.Run("hook may use the cache", func(t *testing.T) {
+		evictedTo := NewTypedCache[string, int](1)
+
+		var self TypedCache[string, int]
+		self = NewTypedCache[string, int](1, WithOnEvict(func(key string, value int, _ EvictReason) {
+			// Would deadlock if the hook ran under the lock.
+			self.Get(key)
+			evictedTo.Set(key, value)
+		}))
+		self.Set("a", 1)
+		self.Set("b", 2)
+
+		val, ok := evictedTo.Get("a")
+		require.True(t, ok)
+		require.Equal(t, 1, val)
+	})
+
+	t.Run("reason string", func(t *testing.T) {
+		require.Equal(t, "capacity", EvictCapacity.String())
+		require.Equal(t, "EvictReason(42)", EvictReason(42).String())
+	})
+}
@@ -1,75 +1,84 @@
 package hw04lrucache
 
-type List interface {
+// List is the doubly linked list of arbitrary values.
+type List = TypedList[interface{}]
+
+type ListItem = TypedListItem[interface{}]
+
+// TypedList is a doubly linked list of values of type T.
+type TypedList[T any] interface {
 	Len() int
-	Front() *ListItem
-	Back() *ListItem
-	PushFront(v interface{}) *ListItem
-	PushBack(v interface{}) *ListItem
-	Remove(i *ListItem)
-	MoveToFront(i *ListItem)
+	Front() *TypedListItem[T]
+	Back() *TypedListItem[T]
+	PushFront(v T) *TypedListItem[T]
+	PushBack(v T) *TypedListItem[T]
+	Remove(i *TypedListItem[T])
+	MoveToFront(i *TypedListItem[T])
 }
 
-type ListItem struct {
-	Value interface{}
-	Next  *ListItem
-	Prev  *ListItem
+type TypedListItem[T any] struct {
+	Value T
+	Next  *TypedListItem[T]
+	Prev  *TypedListItem[T]
 }
 
-type list struct {
-	// Place your code here
-	Head   *ListItem
-	Last   *ListItem
+type list[T any] struct {
+	Head   *TypedListItem[T]
+	Last   *TypedListItem[T]
 	Length int
 }
 
-func (l *list) Len() int {
+func (l *list[T]) Len() int {
 	return l.Length
 }
 
-func (l *list) Front() *ListItem {
+func (l *list[T]) Front() *TypedListItem[T] {
 	return l.Head
 }
 
-func (l *list) Back() *ListItem {
+func (l *list[T]) Back() *TypedListItem[T] {
 	return l.Last
 }
 
-func (l *list) PushFront(v interface{}) *ListItem {
-	if v != nil {
-		newItem := &ListItem{Value: v}
-		if l.Length == 0 {
-			l.Head = newItem
-			l.Last = newItem
-		} else {
-			newItem.Next = l.Head
-			l.Head.Prev = newItem
-			l.Head = newItem
-		}
-		l.Length++
-		return newItem
+// PushFront adds v to the front of the list. A nil interface value is not
+// stored and nil is returned.
+func (l *list[T]) PushFront(v T) *TypedListItem[T] {
+	if isNil(v) {
+		return nil
 	}
-	return nil
+	newItem := &TypedListItem[T]{Value: v}
+	if l.Length == 0 {
+		l.Head = newItem
+		l.Last = newItem
+	} else {
+		newItem.Next = l.Head
+		l.Head.Prev = newItem
+		l.Head = newItem
+	}
+	l.Length++
+	return newItem
 }
 
-func (l *list) PushBack(v interface{}) *ListItem {
-	if v != nil {
-		newItem := &ListItem{Value: v}
-		if l.Length == 0 {
-			l.Head = newItem
-			l.Last = newItem
-		} else {
-			newItem.Prev = l.Last
-			l.Last.Next = newItem
-			l.Last = newItem
-		}
-		l.Length++
-		return newItem
+// PushBack adds v to the back of the list. A nil interface value is not
+// stored and nil is returned.
+func (l *list[T]) PushBack(v T) *TypedListItem[T] {
+	if isNil(v) {
+		return nil
 	}
-	return nil
+	newItem := &TypedListItem[T]{Value: v}
+	if l.Length == 0 {
+		l.Head = newItem
+		l.Last = newItem
+	} else {
+		newItem.Prev = l.Last
+		l.Last.Next = newItem
+		l.Last = newItem
+	}
+	l.Length++
+	return newItem
 }
 
-func (l *list) Remove(i *ListItem) {
+func (l *list[T]) Remove(i *TypedListItem[T]) {
 	if i == nil || l.Length == 0 {
 		return
 	}
@@ -89,7 +98,7 @@ func (l *list) Remove(i *ListItem) {
 	l.Length--
 }
 
-func (l *list) MoveToFront(i *ListItem) {
+func (l *list[T]) MoveToFront(i *TypedListItem[T]) {
 	if i == nil || l.Length == 0 {
 		return
 	}
@@ -97,16 +106,32 @@ func (l *list) MoveToFront(i *ListItem) {
 		return
 	}
 	l.Remove(i)
+	l.insertFront(i)
+}
 
+// insertFront links the item that is not in any list to the front of l.
+func (l *list[T]) insertFront(i *TypedListItem[T]) {
+	i.Prev = nil
 	i.Next = l.Head
-	l.Head.Prev = i
-
+	if l.Length == 0 {
+		l.Last = i
+	} else {
+		l.Head.Prev = i
+	}
 	l.Head = i
-	i.Prev = nil
-
 	l.Length++
 }
 
+// isNil reports whether v is a nil interface value. Values of other types,
+// nil pointers included, are never nil here.
+func isNil[T any](v T) bool {
+	return any(v) == nil
+}
+
 func NewList() List {
-	return new(list)
+	return NewTypedList[interface{}]()
+}
+
+func NewTypedList[T any]() TypedList[T] {
+	return new(list[T])
 }
@@ -150,3 +150,60 @@ func TestListCustom(t *testing.T) {
 		require.Equal(t, []int{70, 80, 60, 40, 10, 30, 50}, elems)
 	})
 }
+
+func TestTypedList(t *testing.T) {
+	l := NewTypedList[string]()
+
+	l.PushBack("b")           // [b]
+	first := l.PushFront("a") // [a, b]
+	l.PushBack("c")           // [a, b, c]
+	l.MoveToFront(l.Back())   // [c, a, b]
+	l.Remove(first)           // [c, b]
+	empty := l.PushBack("")   // [c, b, ""]
+	require.NotNil(t, empty)
+
+	elems := make([]string, 0, l.Len())
+	for i := l.Front(); i != nil; i = i.Next {
+		elems = append(elems, i.Value)
+	}
+	require.Equal(t, []string{"c", "b", ""}, elems)
+
+	t.Run("nil interface values are skipped", func(t *testing.T) {
+		l := NewList()
+
+		require.Nil(t, l.PushFront(nil))
+		require.Nil(t, l.PushBack(nil))
+		require.Equal(t, 0, l.Len())
+	})
+
+	t.Run("nil pointers are stored", func(t *testing.T) {
+		l := NewTypedList[*int]()
+
+		require.NotNil(t, l.PushFront(nil))
+		require.Equal(t, 1, l.Len())
+	})
+}
+
+func BenchmarkList(b *testing.B) {
+	b.Run("interface", func(b *testing.B) {
+		l := NewList()
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l.PushFront(i)
+			if l.Len() > 1024 {
+				l.Remove(l.Back())
+			}
+		}
+	})
+
+	b.Run("typed", func(b *testing.B) {
+		l := NewTypedList[int]()
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l.PushFront(i)
+			if l.Len() > 1024 {
+				l.Remove(l.Back())
+			}
+		}
+	})
+}
@@ -0,0 +1,186 @@
+package hw04lrucache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// LoadFunc fetches the value of the key missing from the cache.
+type LoadFunc[K comparable, V any] func(ctx context.Context, key K) (V, error)
+
+// LoadOption configures a cache created by NewLoadingCache.
+type LoadOption func(*loadOptions)
+
+type loadOptions struct {
+	errorTTL      time.Duration
+	errorCapacity int
+}
+
+// DefaultErrorCapacity is the number of errors remembered by WithErrorTTL
+// when the capacity given is not positive.
+const DefaultErrorCapacity = 1024
+
+// WithErrorTTL makes the cache remember load errors for ttl, so that the
+// failing key is not loaded again until then. At most capacity keys are
+// remembered, DefaultErrorCapacity when it is not positive.
+func WithErrorTTL(ttl time.Duration, capacity int) LoadOption {
+	return func(o *loadOptions) {
+		o.errorTTL = ttl
+		o.errorCapacity = capacity
+	}
+}
+
+// LoadingCache is the cache filling itself on misses. Concurrent loads of
+// the same key are coalesced into one.
+type LoadingCache[K comparable, V any] struct {
+	TypedCache[K, V]
+	errors TypedCache[K, error]
+
+	mu    sync.Mutex
+	calls map[K]*loadCall[V]
+}
+
+// loadCall is a load in progress shared by all the callers waiting for it.
+type loadCall[V any] struct {
+	done    chan struct{}
+	val     V
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// NewLoadingCache wraps the cache, NewLoadingCache[Key, interface{}] accepts
+// a Cache.
+func NewLoadingCache[K comparable, V any](cache TypedCache[K, V], opts ...LoadOption) *LoadingCache[K, V] {
+	var o loadOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	lc := &LoadingCache[K, V]{TypedCache: cache, calls: make(map[K]*loadCall[V])}
+	if o.errorTTL > 0 {
+		if o.errorCapacity <= 0 {
+			o.errorCapacity = DefaultErrorCapacity
+		}
+		lc.errors = newLRUCache[K, error](o.errorCapacity, WithTTL(o.errorTTL))
+	}
+	return lc
+}
+
+// GetOrLoad returns the cached value of the key or loads it with load and
+// caches it. Callers asking for a key being loaded wait for that load. The
+// load runs with the values of ctx of the caller that started it, but is only
+// canceled once every waiting caller gave up. A caller giving up gets the
+// error of its ctx.
+func (lc *LoadingCache[K, V]) GetOrLoad(ctx context.Context, key K, load LoadFunc[K, V]) (V, error) {
+	if val, ok := lc.Get(key); ok {
+		return val, nil
+	}
+
+	lc.mu.Lock()
+	call, ok := lc.calls[key]
+	if !ok {
+		if val, ok := lc.Peek(key); ok {
+			lc.mu.Unlock()
+			return val, nil
+		}
+		if err := lc.loadError(key); err != nil {
+			lc.mu.Unlock()
+			var zero V
+			return zero, err
+		}
+		call = lc.startLoad(ctx, key, load)
+	}
+	call.waiters++
+	lc.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.val, call.err
+	case <-ctx.Done():
+		lc.leave(key, call)
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// loadError returns the remembered error of the last load of the key.
+func (lc *LoadingCache[K, V]) loadError(key K) error {
+	if lc.errors == nil {
+		return nil
+	}
+	err, _ := lc.errors.Get(key)
+	return err
+}
+
+// startLoad runs the load in its own goroutine, so that it outlives the
+// callers leaving early. It is called with lc.mu held.
+func (lc *LoadingCache[K, V]) startLoad(ctx context.Context, key K, load LoadFunc[K, V]) *loadCall[V] {
+	loadCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	call := &loadCall[V]{done: make(chan struct{}), cancel: cancel}
+	lc.calls[key] = call
+
+	go func() {
+		defer cancel()
+		val, err := runLoad(loadCtx, key, load)
+		switch {
+		case err == nil:
+			lc.Set(key, val)
+		case lc.errors != nil && loadCtx.Err() == nil:
+			lc.errors.Set(key, err)
+		}
+
+		lc.mu.Lock()
+		defer lc.mu.Unlock()
+		if lc.calls[key] == call {
+			delete(lc.calls, key)
+		}
+		call.val, call.err = val, err
+		close(call.done)
+	}()
+	return call
+}
+
+// runLoad turns a panic of the loader into an error of the waiting callers.
+func runLoad[K comparable, V any](ctx context.Context, key K, load LoadFunc[K, V]) (val V, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("hw04lrucache: loading %v panicked: %v", key, r)
+		}
+	}()
+	return load(ctx, key)
+}
+
+// leave removes the caller from the waiters of the load, canceling the load
+// when nobody waits for it anymore. The next caller starts a new load.
+func (lc *LoadingCache[K, V]) leave(key K, call *loadCall[V]) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	call.waiters--
+	if call.waiters > 0 {
+		return
+	}
+	call.cancel()
+	if lc.calls[key] == call {
+		delete(lc.calls, key)
+	}
+}
+
+// Delete removes the key and the error of its last load.
+func (lc *LoadingCache[K, V]) Delete(key K) bool {
+	if lc.errors != nil {
+		lc.errors.Delete(key)
+	}
+	return lc.TypedCache.Delete(key)
+}
+
+// Clear removes all the entries and the errors of their loads.
+func (lc *LoadingCache[K, V]) Clear() {
+	if lc.errors != nil {
+		lc.errors.Clear()
+	}
+	lc.TypedCache.Clear()
+}
@@ -0,0 +1,189 @@
+package hw04lrucache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	//nolint:depguard
+	"github.com/stretchr/testify/require"
+)
+
+var errBackend = errors.New("backend is down")
+
+func TestLoadingCache(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("loads and caches", func(t *testing.T) {
+		c := NewLoadingCache[Key, interface{}](NewCache(2))
+		var loads int
+		load := func(_ context.Context, key Key) (interface{}, error) {
+			loads++
+			return string(key) + "!", nil
+		}
+
+		val, err := c.GetOrLoad(ctx, "a", load)
+		require.NoError(t, err)
+		require.Equal(t, "a!", val)
+
+		val, err = c.GetOrLoad(ctx, "a", load)
+		require.NoError(t, err)
+		require.Equal(t, "a!", val)
+		require.Equal(t, 1, loads)
+		require.True(t, c.Contains("a"))
+	})
+
+	t.Run("coalesces concurrent loads", func(t *testing.T) {
+		c := NewLoadingCache(NewTypedCache[string, int](10))
+		var loads atomic.Int32
+		release := make(chan struct{})
+		load := func(context.Context, string) (int, error) {
+			loads.Add(1)
+			<-release
+			return 42, nil
+		}
+
+		const callers = 10
+		var wg sync.WaitGroup
+		results := make([]int, callers)
+		wg.Add(callers)
+		for i := 0; i < callers; i++ {
+			go func(i int) {
+				defer wg.Done()
+				val, err := c.GetOrLoad(ctx, "answer", load)
+				require.NoError(t, err)
+				results[i] = val
+			}(i)
+		}
+		require.Eventually(t, func() bool {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			return c.calls["answer"] != nil && c.calls["answer"].waiters == callers
+		}, time.Second, time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		require.Equal(t, int32(1), loads.Load())
+		for _, val := range results {
+			require.Equal(t, 42, val)
+		}
+	})
+
+	t.Run("errors are not cached by default", func(t *testing.T) {
+		c := NewLoadingCache(NewTypedCache[string, int](10))
+		var loads int
+		load := func(context.Context, string) (int, error) {
+			loads++
+			return 0, errBackend
+		}
+
+		_, err := c.GetOrLoad(ctx, "a", load)
+		require.ErrorIs(t, err, errBackend)
+		_, err = c.GetOrLoad(ctx, "a", load)
+		require.ErrorIs(t, err, errBackend)
+		require.Equal(t, 2, loads)
+		require.False(t, c.Contains("a"))
+	})
+
+	t.Run("error ttl", func(t *testing.T) {
+		clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+		c := NewLoadingCache(NewTypedCache[string, int](10), WithErrorTTL(time.Second, 0))
+		c.errors.(*lruCache[string, error]).now = clock.Now
+		var loads int
+		fail := true
+		load := func(context.Context, string) (int, error) {
+			loads++
+			if fail {
+				return 0, errBackend
+			}
+			return 1, nil
+		}
+
+		_, err := c.GetOrLoad(ctx, "a", load)
+		require.ErrorIs(t, err, errBackend)
+		fail = false
+		_, err = c.GetOrLoad(ctx, "a", load)
+		require.ErrorIs(t, err, errBackend)
+		require.Equal(t, 1, loads)
+
+		clock.Advance(time.Second)
+		val, err := c.GetOrLoad(ctx, "a", load)
+		require.NoError(t, err)
+		require.Equal(t, 1, val)
+		require.Equal(t, 2, loads)
+
+		fail = true
+		c.Delete("a")
+		c.GetOrLoad(ctx, "a", load)
+		c.Delete("a")
+		fail = false
+		_, err = c.GetOrLoad(ctx, "a", load)
+		require.NoError(t, err, "Delete forgets the error")
+	})
+
+	t.Run("waiter cancellation", func(t *testing.T) {
+		c := NewLoadingCache(NewTypedCache[string, int](10))
+		release := make(chan struct{})
+		load := func(context.Context, string) (int, error) {
+			<-release
+			return 7, nil
+		}
+
+		done := make(chan int)
+		go func() {
+			val, err := c.GetOrLoad(ctx, "a", load)
+			require.NoError(t, err)
+			done <- val
+		}()
+
+		waitCtx, cancel := context.WithCancel(ctx)
+		errs := make(chan error)
+		go func() {
+			_, err := c.GetOrLoad(waitCtx, "a", load)
+			errs <- err
+		}()
+		require.Eventually(t, func() bool {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			return c.calls["a"] != nil && c.calls["a"].waiters == 2
+		}, time.Second, time.Millisecond)
+
+		cancel()
+		require.ErrorIs(t, <-errs, context.Canceled)
+
+		close(release)
+		require.Equal(t, 7, <-done, "the load goes on for the other caller")
+	})
+
+	t.Run("load canceled when every caller leaves", func(t *testing.T) {
+		c := NewLoadingCache(NewTypedCache[string, int](10), WithErrorTTL(time.Minute, 10))
+		canceled := make(chan struct{})
+		load := func(ctx context.Context, _ string) (int, error) {
+			<-ctx.Done()
+			close(canceled)
+			return 0, ctx.Err()
+		}
+
+		waitCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		_, err := c.GetOrLoad(waitCtx, "a", load)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		<-canceled
+
+		val, err := c.GetOrLoad(ctx, "a", func(context.Context, string) (int, error) { return 3, nil })
+		require.NoError(t, err, "errors of canceled loads are not cached")
+		require.Equal(t, 3, val)
+	})
+
+	t.Run("loader panic", func(t *testing.T) {
+		c := NewLoadingCache(NewTypedCache[string, int](10))
+		_, err := c.GetOrLoad(ctx, "a", func(context.Context, string) (int, error) {
+			panic("boom")
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "boom")
+	})
+}
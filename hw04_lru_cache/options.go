@@ -0,0 +1,69 @@
+package hw04lrucache
+
+import (
+	"context"
+	"time"
+)
+
+// Option configures a cache of any key and value types. It is an alias of
+// the function type, so that every Option is also a TypedOption.
+type Option = func(*options)
+
+// TypedOption configures a cache with keys of type K and values of type V.
+// The compiler rejects a TypedOption given to a cache of other types.
+type TypedOption[K comparable, V any] func(*options)
+
+type options struct {
+	ttl             time.Duration
+	janitorCtx      context.Context
+	janitorInterval time.Duration
+	now             func() time.Time
+	onEvict         interface{}
+	maxCost         int64
+	sizer           interface{}
+	policy          Policy
+	codec           Codec
+}
+
+func buildOptions[K comparable, V any](opts []TypedOption[K, V]) options {
+	o := options{now: time.Now, codec: GobCodec}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithTTL sets the time to live for entries added with Set.
+func WithTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.ttl = ttl
+	}
+}
+
+// WithJanitor starts a goroutine removing expired entries every interval
+// until ctx is done or the cache is closed. Without it expired entries are
+// only dropped when they are looked up or pushed out by new ones.
+func WithJanitor(ctx context.Context, interval time.Duration) Option {
+	return func(o *options) {
+		o.janitorCtx = ctx
+		o.janitorInterval = interval
+	}
+}
+
+// WithMaxCost switches the cache to weighted mode: every entry has a cost,
+// 1 unless set by WithSizer or SetWithCost, and the least recently used
+// entries are evicted while the total cost exceeds maxCost.
+func WithMaxCost(maxCost int64) Option {
+	return func(o *options) {
+		o.maxCost = maxCost
+	}
+}
+
+// WithSizer sets the function computing the cost of entries added with Set
+// and SetWithTTL. Its key and value types are those of the cache. Values of
+// negative cost are rejected like those over the budget.
+func WithSizer[K comparable, V any](sizer func(key K, value V) int64) TypedOption[K, V] {
+	return func(o *options) {
+		o.sizer = sizer
+	}
+}
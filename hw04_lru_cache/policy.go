@@ -0,0 +1,481 @@
+package hw04lrucache
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Policy selects the entries a cache evicts to make room for new ones.
+type Policy int
+
+const (
+	// PolicyLRU evicts the least recently used entry.
+	PolicyLRU Policy = iota
+	// PolicyLFU evicts the least frequently used entry, the least recently
+	// used one among equally used.
+	PolicyLFU
+	// Policy2Q keeps entries used once in a FIFO queue and promotes them to
+	// the LRU queue only when they are requested again soon after eviction,
+	// so a scan of cold keys does not push out the hot ones.
+	Policy2Q
+	// PolicyARC balances recency and frequency, adapting the share of
+	// entries used once by the keys requested again after their eviction.
+	PolicyARC
+)
+
+var policyNames = map[Policy]string{
+	PolicyLRU: "lru",
+	PolicyLFU: "lfu",
+	Policy2Q:  "2q",
+	PolicyARC: "arc",
+}
+
+func (p Policy) String() string {
+	if name, ok := policyNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("Policy(%d)", int(p))
+}
+
+// ParsePolicy returns the policy named as by Policy.String.
+func ParsePolicy(name string) (Policy, error) {
+	for p, n := range policyNames {
+		if n == name {
+			return p, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown eviction policy %q", name)
+}
+
+// WithPolicy sets the eviction policy, PolicyLRU by default.
+func WithPolicy(p Policy) Option {
+	return func(o *options) {
+		o.policy = p
+	}
+}
+
+// evictionPolicy orders the entries of a cache.
+type evictionPolicy[K comparable, V any] interface {
+	Len() int
+	// Push adds a new entry.
+	Push(elem cacheElement[K, V]) *TypedListItem[cacheElement[K, V]]
+	// Touch records a use of the entry.
+	Touch(item *TypedListItem[cacheElement[K, V]])
+	// Remove drops the entry. Policies may remember the keys of entries
+	// evicted for capacity to recognize them when they come back.
+	Remove(item *TypedListItem[cacheElement[K, V]], reason EvictReason)
+	// Reinsert links back the entry taken out by Remove with EvictDeleted,
+	// keeping its use history.
+	Reinsert(item *TypedListItem[cacheElement[K, V]])
+	// Victim returns the entry to evict next, nil when there are none.
+	Victim() *TypedListItem[cacheElement[K, V]]
+	// Items returns the entries from the most to the least valuable one.
+	Items() []*TypedListItem[cacheElement[K, V]]
+	Resize(capacity int)
+	Reset()
+}
+
+func newEvictionPolicy[K comparable, V any](p Policy, capacity int) evictionPolicy[K, V] {
+	switch p {
+	case PolicyLRU:
+		return &lruPolicy[K, V]{queue: new(list[cacheElement[K, V]])}
+	case PolicyLFU:
+		lfu := &lfuPolicy[K, V]{}
+		lfu.Reset()
+		return lfu
+	case Policy2Q:
+		q := &twoQueuePolicy[K, V]{capacity: capacity}
+		q.Reset()
+		return q
+	case PolicyARC:
+		arc := &arcPolicy[K, V]{capacity: capacity}
+		arc.Reset()
+		return arc
+	}
+	panic(fmt.Sprintf("hw04lrucache: unknown eviction policy %v", p))
+}
+
+// segment tells which list of a policy holds the entry.
+type segment int8
+
+const (
+	// segmentRecent holds entries used once: A1in in 2Q, T1 in ARC.
+	segmentRecent segment = iota
+	// segmentFrequent holds entries used again: Am in 2Q, T2 in ARC.
+	segmentFrequent
+)
+
+func listItems[T any](l *list[T], items []*TypedListItem[T]) []*TypedListItem[T] {
+	for item := l.Front(); item != nil; item = item.Next {
+		items = append(items, item)
+	}
+	return items
+}
+
+type lruPolicy[K comparable, V any] struct {
+	queue *list[cacheElement[K, V]]
+}
+
+func (p *lruPolicy[K, V]) Len() int {
+	return p.queue.Len()
+}
+
+func (p *lruPolicy[K, V]) Push(elem cacheElement[K, V]) *TypedListItem[cacheElement[K, V]] {
+	return p.queue.PushFront(elem)
+}
+
+func (p *lruPolicy[K, V]) Touch(item *TypedListItem[cacheElement[K, V]]) {
+	p.queue.MoveToFront(item)
+}
+
+func (p *lruPolicy[K, V]) Remove(item *TypedListItem[cacheElement[K, V]], _ EvictReason) {
+	p.queue.Remove(item)
+}
+
+func (p *lruPolicy[K, V]) Reinsert(item *TypedListItem[cacheElement[K, V]]) {
+	p.queue.insertFront(item)
+}
+
+func (p *lruPolicy[K, V]) Victim() *TypedListItem[cacheElement[K, V]] {
+	return p.queue.Back()
+}
+
+func (p *lruPolicy[K, V]) Items() []*TypedListItem[cacheElement[K, V]] {
+	return listItems(p.queue, make([]*TypedListItem[cacheElement[K, V]], 0, p.queue.Len()))
+}
+
+func (p *lruPolicy[K, V]) Resize(int) {}
+
+func (p *lruPolicy[K, V]) Reset() {
+	p.queue = new(list[cacheElement[K, V]])
+}
+
+// lfuPolicy keeps a list of entries for every use count, so that all its
+// operations but the removal of the last least used entry take constant time.
+type lfuPolicy[K comparable, V any] struct {
+	buckets map[int]*list[cacheElement[K, V]]
+	minHits int
+	length  int
+}
+
+func (p *lfuPolicy[K, V]) Len() int {
+	return p.length
+}
+
+func (p *lfuPolicy[K, V]) bucket(hits int) *list[cacheElement[K, V]] {
+	b, ok := p.buckets[hits]
+	if !ok {
+		b = new(list[cacheElement[K, V]])
+		p.buckets[hits] = b
+	}
+	return b
+}
+
+func (p *lfuPolicy[K, V]) Push(elem cacheElement[K, V]) *TypedListItem[cacheElement[K, V]] {
+	elem.hits = 1
+	p.minHits = 1
+	p.length++
+	return p.bucket(1).PushFront(elem)
+}
+
+func (p *lfuPolicy[K, V]) Touch(item *TypedListItem[cacheElement[K, V]]) {
+	hits := item.Value.hits
+	p.unlink(item)
+	if p.minHits == hits && p.buckets[hits] == nil {
+		p.minHits = hits + 1
+	}
+	item.Value.hits++
+	p.bucket(hits + 1).insertFront(item)
+}
+
+func (p *lfuPolicy[K, V]) Remove(item *TypedListItem[cacheElement[K, V]], _ EvictReason) {
+	hits := item.Value.hits
+	p.unlink(item)
+	p.length--
+	if p.minHits == hits && p.buckets[hits] == nil {
+		p.minHits = 0
+		for h := range p.buckets {
+			if p.minHits == 0 || h < p.minHits {
+				p.minHits = h
+			}
+		}
+	}
+}
+
+// unlink takes the item out of its bucket, dropping the bucket left empty.
+func (p *lfuPolicy[K, V]) unlink(item *TypedListItem[cacheElement[K, V]]) {
+	b := p.buckets[item.Value.hits]
+	b.Remove(item)
+	if b.Len() == 0 {
+		delete(p.buckets, item.Value.hits)
+	}
+}
+
+func (p *lfuPolicy[K, V]) Reinsert(item *TypedListItem[cacheElement[K, V]]) {
+	hits := item.Value.hits
+	p.bucket(hits).insertFront(item)
+	p.length++
+	if p.minHits == 0 || hits < p.minHits {
+		p.minHits = hits
+	}
+}
+
+func (p *lfuPolicy[K, V]) Victim() *TypedListItem[cacheElement[K, V]] {
+	if b, ok := p.buckets[p.minHits]; ok {
+		return b.Back()
+	}
+	return nil
+}
+
+func (p *lfuPolicy[K, V]) Items() []*TypedListItem[cacheElement[K, V]] {
+	hits := make([]int, 0, len(p.buckets))
+	for h := range p.buckets {
+		hits = append(hits, h)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(hits)))
+
+	items := make([]*TypedListItem[cacheElement[K, V]], 0, p.length)
+	for _, h := range hits {
+		items = listItems(p.buckets[h], items)
+	}
+	return items
+}
+
+func (p *lfuPolicy[K, V]) Resize(int) {}
+
+func (p *lfuPolicy[K, V]) Reset() {
+	p.buckets = make(map[int]*list[cacheElement[K, V]])
+	p.minHits = 0
+	p.length = 0
+}
+
+// twoQueuePolicy is the full version of 2Q by Johnson and Shasha. New entries
+// go to the FIFO queue A1in, the keys evicted from it are remembered in the
+// ghost queue A1out, and the entries added again while remembered go to the
+// LRU queue Am. Hits in A1in are ignored as correlated references.
+type twoQueuePolicy[K comparable, V any] struct {
+	capacity int
+	in       *list[cacheElement[K, V]]
+	main     *list[cacheElement[K, V]]
+	out      *ghostList[K]
+}
+
+// The shares of the capacity for A1in and A1out recommended by the authors.
+const (
+	twoQueueInShare  = 4
+	twoQueueOutShare = 2
+)
+
+// size is the capacity the queues are tuned for, the current number of
+// entries for caches without a limit on it.
+func (p *twoQueuePolicy[K, V]) size() int {
+	if p.capacity > 0 {
+		return p.capacity
+	}
+	return p.Len()
+}
+
+func (p *twoQueuePolicy[K, V]) Len() int {
+	return p.in.Len() + p.main.Len()
+}
+
+func (p *twoQueuePolicy[K, V]) Push(elem cacheElement[K, V]) *TypedListItem[cacheElement[K, V]] {
+	if p.out.remove(elem.Key) {
+		elem.segment = segmentFrequent
+		return p.main.PushFront(elem)
+	}
+	elem.segment = segmentRecent
+	return p.in.PushFront(elem)
+}
+
+func (p *twoQueuePolicy[K, V]) Touch(item *TypedListItem[cacheElement[K, V]]) {
+	if item.Value.segment == segmentFrequent {
+		p.main.MoveToFront(item)
+	}
+}
+
+func (p *twoQueuePolicy[K, V]) Remove(item *TypedListItem[cacheElement[K, V]], reason EvictReason) {
+	if item.Value.segment == segmentFrequent {
+		p.main.Remove(item)
+		return
+	}
+	p.in.Remove(item)
+	if reason == EvictCapacity {
+		p.out.push(item.Value.Key)
+		p.out.trim(max(p.size()/twoQueueOutShare, 1))
+	}
+}
+
+func (p *twoQueuePolicy[K, V]) Reinsert(item *TypedListItem[cacheElement[K, V]]) {
+	if item.Value.segment == segmentFrequent {
+		p.main.insertFront(item)
+	} else {
+		p.in.insertFront(item)
+	}
+}
+
+func (p *twoQueuePolicy[K, V]) Victim() *TypedListItem[cacheElement[K, V]] {
+	if p.in.Len() > max(p.size()/twoQueueInShare, 1) || p.main.Len() == 0 {
+		return p.in.Back()
+	}
+	return p.main.Back()
+}
+
+func (p *twoQueuePolicy[K, V]) Items() []*TypedListItem[cacheElement[K, V]] {
+	items := make([]*TypedListItem[cacheElement[K, V]], 0, p.Len())
+	return listItems(p.in, listItems(p.main, items))
+}
+
+func (p *twoQueuePolicy[K, V]) Resize(capacity int) {
+	p.capacity = capacity
+	p.out.trim(max(p.size()/twoQueueOutShare, 1))
+}
+
+func (p *twoQueuePolicy[K, V]) Reset() {
+	p.in = new(list[cacheElement[K, V]])
+	p.main = new(list[cacheElement[K, V]])
+	p.out = newGhostList[K]()
+}
+
+// arcPolicy is the adaptive replacement cache by Megiddo and Modha. The LRU
+// lists T1 and T2 hold the entries used once and more, the ghost lists B1
+// and B2 remember the keys evicted from them. A key coming back from B1
+// grows the target size p of T1, from B2 shrinks it. Unlike the original,
+// the target is adapted when the entry is added, after the eviction.
+type arcPolicy[K comparable, V any] struct {
+	capacity int
+	target   int
+	t1, t2   *list[cacheElement[K, V]]
+	b1, b2   *ghostList[K]
+}
+
+func (p *arcPolicy[K, V]) size() int {
+	if p.capacity > 0 {
+		return p.capacity
+	}
+	return p.Len()
+}
+
+func (p *arcPolicy[K, V]) Len() int {
+	return p.t1.Len() + p.t2.Len()
+}
+
+func (p *arcPolicy[K, V]) Push(elem cacheElement[K, V]) *TypedListItem[cacheElement[K, V]] {
+	b1, b2 := p.b1.Len(), p.b2.Len()
+	switch {
+	case p.b1.remove(elem.Key):
+		p.target = min(p.target+max(b2/b1, 1), p.size())
+	case p.b2.remove(elem.Key):
+		p.target = max(p.target-max(b1/b2, 1), 0)
+	default:
+		elem.segment = segmentRecent
+		return p.t1.PushFront(elem)
+	}
+	elem.segment = segmentFrequent
+	return p.t2.PushFront(elem)
+}
+
+func (p *arcPolicy[K, V]) Touch(item *TypedListItem[cacheElement[K, V]]) {
+	if item.Value.segment == segmentFrequent {
+		p.t2.MoveToFront(item)
+		return
+	}
+	p.t1.Remove(item)
+	item.Value.segment = segmentFrequent
+	p.t2.insertFront(item)
+}
+
+func (p *arcPolicy[K, V]) Remove(item *TypedListItem[cacheElement[K, V]], reason EvictReason) {
+	if item.Value.segment == segmentFrequent {
+		p.t2.Remove(item)
+		if reason == EvictCapacity {
+			p.b2.push(item.Value.Key)
+		}
+	} else {
+		p.t1.Remove(item)
+		if reason == EvictCapacity {
+			p.b1.push(item.Value.Key)
+		}
+	}
+	p.trimGhosts()
+}
+
+// trimGhosts keeps T1 and B1 within the capacity and all four lists within
+// twice the capacity.
+func (p *arcPolicy[K, V]) trimGhosts() {
+	c := p.size()
+	p.b1.trim(max(c-p.t1.Len(), 0))
+	p.b2.trim(max(2*c-p.Len()-p.b1.Len(), 0))
+}
+
+func (p *arcPolicy[K, V]) Reinsert(item *TypedListItem[cacheElement[K, V]]) {
+	if item.Value.segment == segmentFrequent {
+		p.t2.insertFront(item)
+	} else {
+		p.t1.insertFront(item)
+	}
+}
+
+func (p *arcPolicy[K, V]) Victim() *TypedListItem[cacheElement[K, V]] {
+	if p.t1.Len() > 0 && (p.t1.Len() > p.target || p.t2.Len() == 0) {
+		return p.t1.Back()
+	}
+	return p.t2.Back()
+}
+
+func (p *arcPolicy[K, V]) Items() []*TypedListItem[cacheElement[K, V]] {
+	items := make([]*TypedListItem[cacheElement[K, V]], 0, p.Len())
+	return listItems(p.t1, listItems(p.t2, items))
+}
+
+func (p *arcPolicy[K, V]) Resize(capacity int) {
+	p.capacity = capacity
+	p.target = min(p.target, p.size())
+	p.trimGhosts()
+}
+
+func (p *arcPolicy[K, V]) Reset() {
+	p.target = 0
+	p.t1 = new(list[cacheElement[K, V]])
+	p.t2 = new(list[cacheElement[K, V]])
+	p.b1 = newGhostList[K]()
+	p.b2 = newGhostList[K]()
+}
+
+// ghostList is an LRU list of the keys of evicted entries.
+type ghostList[K comparable] struct {
+	keys  *list[K]
+	items map[K]*TypedListItem[K]
+}
+
+func newGhostList[K comparable]() *ghostList[K] {
+	return &ghostList[K]{keys: new(list[K]), items: make(map[K]*TypedListItem[K])}
+}
+
+func (g *ghostList[K]) Len() int {
+	return g.keys.Len()
+}
+
+func (g *ghostList[K]) push(key K) {
+	g.remove(key)
+	if item := g.keys.PushFront(key); item != nil {
+		g.items[key] = item
+	}
+}
+
+// remove forgets the key and reports whether it was remembered.
+func (g *ghostList[K]) remove(key K) bool {
+	item, ok := g.items[key]
+	if ok {
+		g.keys.Remove(item)
+		delete(g.items, key)
+	}
+	return ok
+}
+
+// trim forgets the oldest keys beyond the first n.
+func (g *ghostList[K]) trim(n int) {
+	for g.keys.Len() > n {
+		g.remove(g.keys.Back().Value)
+	}
+}
@@ -0,0 +1,200 @@
+package hw04lrucache
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	//nolint:depguard
+	"github.com/stretchr/testify/require"
+)
+
+var allPolicies = []Policy{PolicyLRU, PolicyLFU, Policy2Q, PolicyARC}
+
+func TestPolicies(t *testing.T) {
+	for _, p := range allPolicies {
+		p := p
+		t.Run(p.String(), func(t *testing.T) {
+			rec := &evictRecorder{}
+			c := NewCache(3, WithPolicy(p), WithOnEvict(rec.hook))
+
+			require.False(t, c.Set("a", 1))
+			require.False(t, c.Set("b", 2))
+			require.True(t, c.Set("a", 10))
+			val, ok := c.Get("a")
+			require.True(t, ok)
+			require.Equal(t, 10, val)
+
+			for i := 0; i < 100; i++ {
+				c.Set(Key(strconv.Itoa(i)), i)
+				require.LessOrEqual(t, c.Len(), 3)
+			}
+			require.Len(t, c.Keys(), 3)
+			require.Len(t, rec.get(), 99)
+
+			require.Equal(t, 1, c.Resize(2))
+			require.Len(t, c.Keys(), 2)
+
+			c.Clear()
+			require.Equal(t, 0, c.Len())
+			c.Set("z", 26)
+			require.Equal(t, []Key{"z"}, c.Keys())
+		})
+	}
+}
+
+func TestPoliciesWeightedUpdate(t *testing.T) {
+	for _, p := range allPolicies {
+		p := p
+		t.Run(p.String(), func(t *testing.T) {
+			rec := &evictRecorder{}
+			c := NewCache(0, WithPolicy(p), WithMaxCost(10), WithOnEvict(rec.hook))
+			c.SetWithCost("a", 1, 3)
+			for i := 0; i < 5; i++ {
+				c.Get("a")
+			}
+			c.SetWithCost("c", 3, 3)
+
+			wasInCache, err := c.SetWithCost("c", 2, 8)
+			require.NoError(t, err)
+			require.True(t, wasInCache)
+			val, ok := c.Get("c")
+			require.True(t, ok, "the updated entry is not evicted for itself")
+			require.Equal(t, 2, val)
+			require.Equal(t, []Key{"c"}, c.Keys())
+			require.Equal(t, []evictedEntry{{"a", 1, EvictCapacity}}, rec.get())
+			require.Equal(t, int64(8), c.Stats().Cost)
+
+			c.SetWithCost("b", 2, 2)
+			c.SetWithCost("c", 4, 9)
+			require.Equal(t, []Key{"c"}, c.Keys())
+			require.Equal(t, 1, c.Len())
+		})
+	}
+}
+
+func TestPolicyLFU(t *testing.T) {
+	c := NewCache(3, WithPolicy(PolicyLFU))
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Set("c", 3)
+	c.Get("a")
+	c.Get("a")
+	c.Get("b")
+	c.Get("c")
+
+	c.Set("d", 4)
+	require.Equal(t, []Key{"a", "c", "d"}, c.Keys(), "b is the least used")
+
+	c = NewCache(2, WithPolicy(PolicyLFU))
+	c.Set("a", 1)
+	c.Get("a")
+	c.Set("b", 2)
+	c.Set("c", 3)
+	require.Equal(t, []Key{"a", "c"}, c.Keys())
+
+	require.True(t, c.Delete("a"))
+	c.Set("d", 4)
+	c.Set("e", 5)
+	require.Equal(t, []Key{"e", "d"}, c.Keys())
+}
+
+func TestPolicy2Q(t *testing.T) {
+	c := NewCache(8, WithPolicy(Policy2Q))
+	for _, key := range []Key{"a", "b", "c"} {
+		c.Set(key, key)
+	}
+	// Push the first keys out of A1in, so they are remembered in A1out.
+	for i := 0; i < 8; i++ {
+		c.Set(Key(strconv.Itoa(i)), i)
+	}
+	require.False(t, c.Contains("a"))
+
+	c.Set("a", "a")
+	for i := 10; i < 30; i++ {
+		c.Set(Key(strconv.Itoa(i)), i)
+	}
+	require.True(t, c.Contains("a"), "a survives the scan in Am")
+}
+
+func TestPolicyARC(t *testing.T) {
+	c := NewCache(4, WithPolicy(PolicyARC))
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Get("a")
+	c.Get("b")
+
+	for i := 0; i < 20; i++ {
+		c.Set(Key(strconv.Itoa(i)), i)
+	}
+	require.True(t, c.Contains("a"))
+	require.True(t, c.Contains("b"))
+}
+
+func TestParsePolicy(t *testing.T) {
+	for _, p := range allPolicies {
+		parsed, err := ParsePolicy(p.String())
+		require.NoError(t, err)
+		require.Equal(t, p, parsed)
+	}
+	_, err := ParsePolicy("mru")
+	require.Error(t, err)
+	require.Equal(t, "Policy(42)", Policy(42).String())
+	require.Panics(t, func() { NewCache(1, WithPolicy(Policy(42))) })
+}
+
+// scanTrace requests a hot set of keys twice in a row, then scans cold keys
+// requested once, so that the hot set is used again only after the scan.
+func scanTrace(hot, scan, rounds int) []Key {
+	var trace []Key
+	cold := 0
+	for r := 0; r < rounds; r++ {
+		for i := 0; i < 2; i++ {
+			for h := 0; h < hot; h++ {
+				trace = append(trace, Key("hot"+strconv.Itoa(h)))
+			}
+		}
+		for s := 0; s < scan; s++ {
+			trace = append(trace, Key("cold"+strconv.Itoa(cold)))
+			cold++
+		}
+	}
+	return trace
+}
+
+func TestSimulate(t *testing.T) {
+	results := Simulate(scanTrace(20, 50, 50), 60, allPolicies...)
+	require.Len(t, results, len(allPolicies))
+
+	ratios := make(map[Policy]float64)
+	for i, r := range results {
+		require.Equal(t, allPolicies[i], r.Policy)
+		require.Equal(t, 50*(2*20+50), r.Hits+r.Misses)
+		ratios[r.Policy] = r.HitRatio()
+	}
+	require.Greater(t, ratios[Policy2Q], ratios[PolicyLRU], "2Q resists scans")
+	require.Greater(t, ratios[PolicyARC], ratios[PolicyLRU], "ARC resists scans")
+	require.Greater(t, ratios[PolicyLFU], ratios[PolicyLRU], "LFU resists scans")
+
+	fromReader, err := SimulateReader(strings.NewReader("a\nb\na\na\n"), 1, PolicyLRU)
+	require.NoError(t, err)
+	require.Equal(t, []SimulationResult{{Policy: PolicyLRU, Hits: 1, Misses: 3}}, fromReader)
+	require.InDelta(t, 0.25, fromReader[0].HitRatio(), 1e-9)
+	require.Zero(t, SimulationResult{}.HitRatio())
+}
+
+func BenchmarkPolicies(b *testing.B) {
+	trace := scanTrace(200, 500, 20)
+	for _, p := range allPolicies {
+		b.Run(p.String(), func(b *testing.B) {
+			c := NewCache(1000, WithPolicy(p))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				key := trace[i%len(trace)]
+				if _, ok := c.Get(key); !ok {
+					c.Set(key, i)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package hw04lrucache
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// StatsProvider is anything reporting cache statistics, as every cache does.
+type StatsProvider interface {
+	Stats() Stats
+}
+
+type metric struct {
+	name  string
+	kind  string
+	help  string
+	value func(s Stats) int64
+}
+
+var metrics = []metric{
+	{
+		"cache_hits_total", "counter", "Lookups that found the value.",
+		func(s Stats) int64 { return int64(s.Hits) },
+	},
+	{
+		"cache_misses_total", "counter", "Lookups that did not find the value.",
+		func(s Stats) int64 { return int64(s.Misses) },
+	},
+	{
+		"cache_evictions_total", "counter", "Entries removed to make room for new ones.",
+		func(s Stats) int64 { return int64(s.Evictions) },
+	},
+	{
+		"cache_expirations_total", "counter", "Entries removed after their TTL passed.",
+		func(s Stats) int64 { return int64(s.Expirations) },
+	},
+	{
+		"cache_overwrites_total", "counter", "Values set for keys already in the cache.",
+		func(s Stats) int64 { return int64(s.Overwrites) },
+	},
+	{
+		"cache_entries", "gauge", "Entries in the cache.",
+		func(s Stats) int64 { return int64(s.Entries) },
+	},
+	{
+		"cache_cost", "gauge", "Total cost of the entries in the cache.",
+		func(s Stats) int64 { return s.Cost },
+	},
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// WritePrometheus writes the statistics of the caches in the Prometheus text
+// exposition format, telling the caches apart by the cache label.
+func WritePrometheus(w io.Writer, caches map[string]StatsProvider) error {
+	names := make([]string, 0, len(caches))
+	for name := range caches {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	stats := make([]Stats, len(names))
+	for i, name := range names {
+		stats[i] = caches[name].Stats()
+	}
+
+	bw := bufio.NewWriter(w)
+	for _, m := range metrics {
+		fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.kind)
+		for i, name := range names {
+			fmt.Fprintf(bw, "%s{cache=\"%s\"} %d\n", m.name, labelEscaper.Replace(name), m.value(stats[i]))
+		}
+	}
+	return bw.Flush()
+}
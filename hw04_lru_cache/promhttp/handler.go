@@ -0,0 +1,23 @@
+// Package promhttp serves cache statistics to Prometheus over HTTP.
+package promhttp
+
+import (
+	"bytes"
+	"net/http"
+
+	hw04lrucache "github.com/Nickolas990/otus_hw/hw04_lru_cache"
+)
+
+// Handler serves the statistics of the caches for scraping. The metrics are
+// written in full before the response, so that a failure gives status 500.
+func Handler(caches map[string]hw04lrucache.StatsProvider) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		var buf bytes.Buffer
+		if err := hw04lrucache.WritePrometheus(&buf, caches); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		w.Write(buf.Bytes())
+	})
+}
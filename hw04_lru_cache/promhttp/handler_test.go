@@ -0,0 +1,23 @@
+package promhttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	hw04lrucache "github.com/Nickolas990/otus_hw/hw04_lru_cache"
+	//nolint:depguard
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	users := hw04lrucache.NewCache(1)
+	users.Set("a", 1)
+
+	rec := httptest.NewRecorder()
+	Handler(map[string]hw04lrucache.StatsProvider{"users": users}).
+		ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Header().Get("Content-Type"), "text/plain; version=0.0.4")
+	require.Contains(t, rec.Body.String(), `cache_entries{cache="users"} 1`)
+}
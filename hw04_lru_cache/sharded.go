@@ -0,0 +1,145 @@
+package hw04lrucache
+
+import (
+	"hash/maphash"
+	"math/bits"
+	"runtime"
+	"time"
+)
+
+// shardedCache spreads keys over independent LRU caches, each with its own
+// lock, so that goroutines working with different keys rarely contend.
+// Recency is tracked per shard: the entry evicted is the least recently used
+// one of its shard, not of the whole cache.
+type shardedCache[K comparable, V any] struct {
+	shards []*lruCache[K, V]
+	mask   uint64
+	hash   func(key K) uint64
+}
+
+// NewShardedCache creates the cache of shards LRU caches sharing capacity
+// entries between them. The number of shards is rounded up to a power of
+// two, a non-positive one means GOMAXPROCS. Options apply to every shard,
+// WithMaxCost budget is split between the shards like the capacity.
+func NewShardedCache(shards, capacity int, opts ...TypedOption[Key, interface{}]) Cache {
+	seed := maphash.MakeSeed()
+	return newShardedCache[Key, interface{}](shards, capacity, func(key Key) uint64 {
+		return maphash.String(seed, string(key))
+	}, opts...)
+}
+
+// NewTypedShardedCache is NewShardedCache for keys of type K, hash must
+// return the same value for equal keys.
+func NewTypedShardedCache[K comparable, V any](
+	shards, capacity int, hash func(key K) uint64, opts ...TypedOption[K, V],
+) TypedCache[K, V] {
+	return newShardedCache[K, V](shards, capacity, hash, opts...)
+}
+
+func newShardedCache[K comparable, V any](
+	shards, capacity int, hash func(key K) uint64, opts ...TypedOption[K, V],
+) *shardedCache[K, V] {
+	if shards <= 0 {
+		shards = runtime.GOMAXPROCS(0)
+	}
+	if shards > 1 {
+		shards = 1 << bits.Len(uint(shards-1))
+	}
+
+	o := buildOptions(opts)
+	if o.maxCost > 0 {
+		o.maxCost = (o.maxCost + int64(shards) - 1) / int64(shards)
+	}
+	sc := &shardedCache[K, V]{
+		shards: make([]*lruCache[K, V], shards),
+		mask:   uint64(shards - 1),
+		hash:   hash,
+	}
+	for i := range sc.shards {
+		sc.shards[i] = newLRUCacheWithOptions[K, V](shardCapacity(capacity, shards), o)
+	}
+	return sc
+}
+
+// shardCapacity rounds up so that the shards hold at least capacity entries.
+func shardCapacity(capacity, shards int) int {
+	if capacity <= 0 {
+		return capacity
+	}
+	return (capacity + shards - 1) / shards
+}
+
+func (sc *shardedCache[K, V]) shard(key K) *lruCache[K, V] {
+	return sc.shards[sc.hash(key)&sc.mask]
+}
+
+func (sc *shardedCache[K, V]) Set(key K, value V) bool {
+	return sc.shard(key).Set(key, value)
+}
+
+func (sc *shardedCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) bool {
+	return sc.shard(key).SetWithTTL(key, value, ttl)
+}
+
+func (sc *shardedCache[K, V]) SetWithCost(key K, value V, cost int64) (bool, error) {
+	return sc.shard(key).SetWithCost(key, value, cost)
+}
+
+func (sc *shardedCache[K, V]) Get(key K) (V, bool) {
+	return sc.shard(key).Get(key)
+}
+
+func (sc *shardedCache[K, V]) Peek(key K) (V, bool) {
+	return sc.shard(key).Peek(key)
+}
+
+func (sc *shardedCache[K, V]) Contains(key K) bool {
+	return sc.shard(key).Contains(key)
+}
+
+func (sc *shardedCache[K, V]) Delete(key K) bool {
+	return sc.shard(key).Delete(key)
+}
+
+func (sc *shardedCache[K, V]) Len() int {
+	n := 0
+	for _, shard := range sc.shards {
+		n += shard.Len()
+	}
+	return n
+}
+
+// Keys returns the keys shard by shard, each shard from the most recently
+// used to the least recently used one.
+func (sc *shardedCache[K, V]) Keys() []K {
+	var keys []K
+	for _, shard := range sc.shards {
+		keys = append(keys, shard.Keys()...)
+	}
+	return keys
+}
+
+// Resize splits the new capacity between the shards and returns how many
+// entries they evicted in total.
+func (sc *shardedCache[K, V]) Resize(capacity int) int {
+	if capacity <= 0 {
+		return 0
+	}
+	removed := 0
+	for _, shard := range sc.shards {
+		removed += shard.Resize(shardCapacity(capacity, len(sc.shards)))
+	}
+	return removed
+}
+
+func (sc *shardedCache[K, V]) Clear() {
+	for _, shard := range sc.shards {
+		shard.Clear()
+	}
+}
+
+func (sc *shardedCache[K, V]) Close() {
+	for _, shard := range sc.shards {
+		shard.Close()
+	}
+}
@@ -0,0 +1,133 @@
+package hw04lrucache
+
+import (
+	"math/rand"
+	"strconv"
+	"sync"
+	"testing"
+
+	//nolint:depguard
+	"github.com/stretchr/testify/require"
+)
+
+func TestShardedCache(t *testing.T) {
+	t.Run("simple", func(t *testing.T) {
+		c := NewShardedCache(4, 100)
+
+		require.False(t, c.Set("aaa", 100))
+		require.False(t, c.Set("bbb", 200))
+		require.True(t, c.Set("aaa", 300))
+
+		val, ok := c.Get("aaa")
+		require.True(t, ok)
+		require.Equal(t, 300, val)
+		require.True(t, c.Contains("bbb"))
+		require.Equal(t, 2, c.Len())
+		require.ElementsMatch(t, []Key{"aaa", "bbb"}, c.Keys())
+
+		require.True(t, c.Delete("bbb"))
+		_, ok = c.Get("bbb")
+		require.False(t, ok)
+
+		c.Clear()
+		require.Equal(t, 0, c.Len())
+	})
+
+	t.Run("shard count", func(t *testing.T) {
+		sc := newShardedCache[Key, interface{}](5, 16, func(Key) uint64 { return 0 })
+		require.Len(t, sc.shards, 8)
+		require.Equal(t, 2, sc.shards[0].capacity)
+
+		sc = newShardedCache[Key, interface{}](0, 16, func(Key) uint64 { return 0 })
+		require.NotEmpty(t, sc.shards)
+	})
+
+	t.Run("per-shard eviction", func(t *testing.T) {
+		// All keys land in the first shard, which holds two entries.
+		c := NewTypedShardedCache[int, int](2, 4, func(int) uint64 { return 0 })
+		c.Set(1, 1)
+		c.Set(2, 2)
+		c.Get(1)
+		c.Set(3, 3)
+
+		require.Equal(t, []int{3, 1}, c.Keys())
+	})
+
+	t.Run("capacity", func(t *testing.T) {
+		c := NewShardedCache(4, 40)
+		for i := 0; i < 1000; i++ {
+			c.Set(Key(strconv.Itoa(i)), i)
+		}
+		require.LessOrEqual(t, c.Len(), 40)
+
+		c.Resize(8)
+		require.LessOrEqual(t, c.Len(), 8)
+	})
+
+	t.Run("cost budget", func(t *testing.T) {
+		c := NewTypedShardedCache[int, int](2, 0, func(key int) uint64 { return uint64(key) }, WithMaxCost(10))
+
+		_, err := c.SetWithCost(0, 0, 6)
+		require.ErrorIs(t, err, ErrTooLarge)
+		_, err = c.SetWithCost(1, 1, 5)
+		require.NoError(t, err)
+		_, err = c.SetWithCost(3, 3, 5)
+		require.NoError(t, err)
+		require.Equal(t, []int{3}, c.Keys())
+	})
+}
+
+func TestShardedCacheMultithreading(_ *testing.T) {
+	c := NewShardedCache(8, 10)
+	wg := &sync.WaitGroup{}
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1_000_000; i++ {
+			c.Set(Key(strconv.Itoa(i)), i)
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1_000_000; i++ {
+			c.Get(Key(strconv.Itoa(rand.Intn(1_000_000))))
+		}
+	}()
+
+	wg.Wait()
+}
+
+func BenchmarkCacheParallel(b *testing.B) {
+	const capacity = 1 << 14
+	keys := make([]Key, 4*capacity)
+	for i := range keys {
+		keys[i] = Key(strconv.Itoa(i))
+	}
+
+	caches := []struct {
+		name string
+		new  func() Cache
+	}{
+		{"lru", func() Cache { return NewCache(capacity) }},
+		{"sharded", func() Cache { return NewShardedCache(0, capacity) }},
+	}
+
+	for _, tc := range caches {
+		b.Run(tc.name, func(b *testing.B) {
+			c := tc.new()
+			b.ReportAllocs()
+			b.RunParallel(func(pb *testing.PB) {
+				i := rand.Intn(len(keys))
+				for pb.Next() {
+					key := keys[i%len(keys)]
+					if _, ok := c.Get(key); !ok {
+						c.Set(key, i)
+					}
+					i++
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package hw04lrucache
+
+import (
+	"bufio"
+	"io"
+)
+
+// SimulationResult is the outcome of replaying a key trace against a cache.
+type SimulationResult struct {
+	Policy Policy
+	Hits   int
+	Misses int
+}
+
+func (r SimulationResult) HitRatio() float64 {
+	if r.Hits+r.Misses == 0 {
+		return 0
+	}
+	return float64(r.Hits) / float64(r.Hits+r.Misses)
+}
+
+// Simulate replays the trace of requested keys against a cache of the given
+// capacity for every policy. A key missing from the cache is added to it, as
+// when the cache fronts a slower store.
+func Simulate(trace []Key, capacity int, policies ...Policy) []SimulationResult {
+	sim := newSimulator(capacity, policies)
+	for _, key := range trace {
+		sim.request(key)
+	}
+	return sim.results
+}
+
+// SimulateReader is Simulate for a trace read from r, one key per line.
+func SimulateReader(r io.Reader, capacity int, policies ...Policy) ([]SimulationResult, error) {
+	sim := newSimulator(capacity, policies)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		sim.request(Key(scanner.Text()))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return sim.results, nil
+}
+
+type simulator struct {
+	caches  []Cache
+	results []SimulationResult
+}
+
+func newSimulator(capacity int, policies []Policy) *simulator {
+	sim := &simulator{
+		caches:  make([]Cache, len(policies)),
+		results: make([]SimulationResult, len(policies)),
+	}
+	for i, p := range policies {
+		sim.caches[i] = NewCache(capacity, WithPolicy(p))
+		sim.results[i].Policy = p
+	}
+	return sim
+}
+
+func (s *simulator) request(key Key) {
+	for i, c := range s.caches {
+		if _, ok := c.Get(key); ok {
+			s.results[i].Hits++
+			continue
+		}
+		s.results[i].Misses++
+		c.Set(key, struct{}{})
+	}
+}
@@ -0,0 +1,210 @@
+package hw04lrucache
+
+import (
+	"bufio"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+var (
+	ErrInvalidSnapshot   = errors.New("invalid cache snapshot")
+	ErrTruncatedSnapshot = errors.New("cache snapshot is truncated")
+)
+
+// Codec encodes the header and the entries of cache snapshots.
+type Codec interface {
+	NewEncoder(w io.Writer) Encoder
+	NewDecoder(r io.Reader) Decoder
+}
+
+type Encoder interface {
+	Encode(v interface{}) error
+}
+
+type Decoder interface {
+	Decode(v interface{}) error
+}
+
+type gobCodec struct{}
+
+func (gobCodec) NewEncoder(w io.Writer) Encoder { return gob.NewEncoder(w) }
+func (gobCodec) NewDecoder(r io.Reader) Decoder { return gob.NewDecoder(r) }
+
+type jsonCodec struct{}
+
+func (jsonCodec) NewEncoder(w io.Writer) Encoder { return json.NewEncoder(w) }
+func (jsonCodec) NewDecoder(r io.Reader) Decoder { return json.NewDecoder(r) }
+
+var (
+	// GobCodec is the default codec. Concrete types stored in interface
+	// values, as by NewCache, must be registered with gob.Register.
+	GobCodec Codec = gobCodec{}
+	// JSONCodec writes an entry per line. Values restored into interface
+	// values get the types of encoding/json: float64, string, map and so on.
+	JSONCodec Codec = jsonCodec{}
+)
+
+// WithCodec sets the codec of Snapshot and Restore, GobCodec by default.
+func WithCodec(codec Codec) Option {
+	return func(o *options) {
+		o.codec = codec
+	}
+}
+
+const (
+	snapshotFormat  = "hw04lrucache"
+	snapshotVersion = 1
+)
+
+type snapshotHeader struct {
+	Format  string `json:"format"`
+	Version int    `json:"version"`
+}
+
+type snapshotEntry[K comparable, V any] struct {
+	Key       K         `json:"key"`
+	Value     V         `json:"value"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	Cost      int64     `json:"cost"`
+}
+
+// Snapshot writes the entries from the most to the least recently used, or
+// in the order of the eviction policy. Expired entries are skipped.
+func (lru *lruCache[K, V]) Snapshot(w io.Writer) error {
+	return writeSnapshot(w, lru.codec, lru.snapshotEntries())
+}
+
+// Restore adds the entries of the snapshot keeping their order, TTL and
+// cost, as if they were set from the last to the first one. Only as many
+// first entries as the cache holds are read. A truncated snapshot gives
+// ErrTruncatedSnapshot, but the entries read completely are restored.
+func (lru *lruCache[K, V]) Restore(r io.Reader) error {
+	entries, err := readSnapshot[K, V](r, lru.codec, lru.limit())
+	lru.restoreEntries(entries)
+	return err
+}
+
+func (lru *lruCache[K, V]) snapshotEntries() []snapshotEntry[K, V] {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	now := lru.now()
+	items := lru.policy.Items()
+	entries := make([]snapshotEntry[K, V], 0, len(items))
+	for _, item := range items {
+		if e := item.Value; !e.expired(now) {
+			entries = append(entries, snapshotEntry[K, V]{Key: e.Key, Value: e.Val, ExpiresAt: e.ExpiresAt, Cost: e.Cost})
+		}
+	}
+	return entries
+}
+
+func (lru *lruCache[K, V]) restoreEntries(entries []snapshotEntry[K, V]) {
+	var evicted evictions[K, V]
+	defer func() { lru.notify(evicted) }()
+
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	now := lru.now()
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		elem := cacheElement[K, V]{Key: e.Key, Val: e.Value, ExpiresAt: e.ExpiresAt, Cost: e.Cost}
+		if elem.expired(now) || lru.checkCost(elem.Cost) != nil {
+			continue
+		}
+		lru.put(elem, now, &evicted)
+	}
+}
+
+// limit returns the number of entries the cache holds, 0 when unlimited.
+func (lru *lruCache[K, V]) limit() int {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	return max(lru.capacity, 0)
+}
+
+// Snapshot writes the entries shard by shard.
+func (sc *shardedCache[K, V]) Snapshot(w io.Writer) error {
+	var entries []snapshotEntry[K, V]
+	for _, shard := range sc.shards {
+		entries = append(entries, shard.snapshotEntries()...)
+	}
+	return writeSnapshot(w, sc.shards[0].codec, entries)
+}
+
+// Restore adds the entries of the snapshot to their shards like
+// lruCache.Restore, keeping their order within every shard.
+func (sc *shardedCache[K, V]) Restore(r io.Reader) error {
+	limit := 0
+	for _, shard := range sc.shards {
+		shardLimit := shard.limit()
+		if shardLimit == 0 {
+			limit = 0
+			break
+		}
+		limit += shardLimit
+	}
+	entries, err := readSnapshot[K, V](r, sc.shards[0].codec, limit)
+
+	shardEntries := make([][]snapshotEntry[K, V], len(sc.shards))
+	for _, e := range entries {
+		i := sc.hash(e.Key) & sc.mask
+		shardEntries[i] = append(shardEntries[i], e)
+	}
+	for i, shard := range sc.shards {
+		shard.restoreEntries(shardEntries[i])
+	}
+	return err
+}
+
+func writeSnapshot[K comparable, V any](w io.Writer, codec Codec, entries []snapshotEntry[K, V]) error {
+	bw := bufio.NewWriter(w)
+	enc := codec.NewEncoder(bw)
+	if err := enc.Encode(snapshotHeader{Format: snapshotFormat, Version: snapshotVersion}); err != nil {
+		return err
+	}
+	for i := range entries {
+		if err := enc.Encode(&entries[i]); err != nil {
+			return fmt.Errorf("encode entry %v: %w", entries[i].Key, err)
+		}
+	}
+	return bw.Flush()
+}
+
+// readSnapshot decodes at most limit entries, all of them when limit is 0.
+func readSnapshot[K comparable, V any](r io.Reader, codec Codec, limit int) ([]snapshotEntry[K, V], error) {
+	dec := codec.NewDecoder(bufio.NewReader(r))
+
+	var header snapshotHeader
+	if err := dec.Decode(&header); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, ErrTruncatedSnapshot
+		}
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSnapshot, err)
+	}
+	if header.Format != snapshotFormat || header.Version != snapshotVersion {
+		return nil, fmt.Errorf("%w: format %q version %d", ErrInvalidSnapshot, header.Format, header.Version)
+	}
+
+	var entries []snapshotEntry[K, V]
+	for limit == 0 || len(entries) < limit {
+		var e snapshotEntry[K, V]
+		err := dec.Decode(&e)
+		switch {
+		case errors.Is(err, io.EOF):
+			return entries, nil
+		case errors.Is(err, io.ErrUnexpectedEOF):
+			return entries, ErrTruncatedSnapshot
+		case err != nil:
+			return entries, fmt.Errorf("decode entry %d: %w", len(entries), err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
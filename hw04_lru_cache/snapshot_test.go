@@ -0,0 +1,131 @@
+package hw04lrucache
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	//nolint:depguard
+	"github.com/stretchr/testify/require"
+)
+
+type user struct {
+	Name string
+	Age  int
+}
+
+func TestSnapshot(t *testing.T) {
+	for _, codec := range []struct {
+		name  string
+		codec Codec
+	}{{"gob", GobCodec}, {"json", JSONCodec}} {
+		codec := codec
+		t.Run(codec.name, func(t *testing.T) {
+			c := NewTypedCache[string, user](5, WithCodec(codec.codec))
+			c.Set("ann", user{"Ann", 30})
+			c.Set("bob", user{"Bob", 25})
+			c.Set("eve", user{"Eve", 41})
+			c.Get("ann")
+
+			var buf bytes.Buffer
+			require.NoError(t, c.Snapshot(&buf))
+
+			restored := NewTypedCache[string, user](5, WithCodec(codec.codec))
+			require.NoError(t, restored.Restore(bytes.NewReader(buf.Bytes())))
+			require.Equal(t, []string{"ann", "eve", "bob"}, restored.Keys())
+			val, ok := restored.Peek("bob")
+			require.True(t, ok)
+			require.Equal(t, user{"Bob", 25}, val)
+		})
+	}
+
+	t.Run("ttl and cost", func(t *testing.T) {
+		c, clock := newTestCache(5, WithMaxCost(10))
+		c.SetWithTTL("a", 1, time.Second)
+		c.SetWithTTL("b", 2, time.Minute)
+		c.SetWithCost("c", "three", 4)
+
+		var buf bytes.Buffer
+		require.NoError(t, c.Snapshot(&buf))
+
+		clock.Advance(time.Second)
+		restored := newLRUCache[Key, interface{}](5, WithMaxCost(10), func(o *options) { o.now = clock.Now })
+		require.NoError(t, restored.Restore(&buf))
+		require.Equal(t, []Key{"c", "b"}, restored.Keys(), "a has expired")
+		require.Equal(t, int64(5), restored.Stats().Cost)
+
+		clock.Advance(time.Minute)
+		require.False(t, restored.Contains("b"))
+	})
+
+	t.Run("capacity", func(t *testing.T) {
+		c := NewCache(5)
+		for i := 0; i < 5; i++ {
+			c.Set(Key(strconv.Itoa(i)), i)
+		}
+		var buf bytes.Buffer
+		require.NoError(t, c.Snapshot(&buf))
+
+		rec := &evictRecorder{}
+		restored := NewCache(3, WithOnEvict(rec.hook))
+		require.NoError(t, restored.Restore(&buf))
+		require.Equal(t, []Key{"4", "3", "2"}, restored.Keys())
+		require.Empty(t, rec.get(), "entries that do not fit are not read")
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		for _, codec := range []Codec{GobCodec, JSONCodec} {
+			c := NewCache(10, WithCodec(codec))
+			for i := 0; i < 10; i++ {
+				c.Set(Key(strconv.Itoa(i)), strings.Repeat("x", i))
+			}
+			keys := c.Keys()
+			var buf bytes.Buffer
+			require.NoError(t, c.Snapshot(&buf))
+			data := buf.Bytes()
+
+			for n := 0; n < len(data); n++ {
+				restored := NewCache(10, WithCodec(codec))
+				err := restored.Restore(bytes.NewReader(data[:n]))
+				if err != nil {
+					require.ErrorIs(t, err, ErrTruncatedSnapshot, "cut at %d", n)
+				}
+				restoredKeys := restored.Keys()
+				require.Equal(t, keys[:len(restoredKeys)], restoredKeys, "cut at %d", n)
+			}
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		c := NewCache(3, WithCodec(JSONCodec))
+		err := c.Restore(strings.NewReader(`{"format": "other", "version": 1}`))
+		require.ErrorIs(t, err, ErrInvalidSnapshot)
+
+		err = c.Restore(strings.NewReader(`not json`))
+		require.ErrorIs(t, err, ErrInvalidSnapshot)
+
+		err = c.Restore(strings.NewReader(`{"format": "hw04lrucache", "version": 1}` + "\n" +
+			`{"key": "a", "value": 1}` + "\n" + `{"key": 2}`))
+		require.Error(t, err)
+		require.Equal(t, []Key{"a"}, c.Keys())
+	})
+
+	t.Run("sharded", func(t *testing.T) {
+		// Keys spread over shards unevenly, so none of them must fill up.
+		c := NewShardedCache(4, 400)
+		for i := 0; i < 20; i++ {
+			c.Set(Key(strconv.Itoa(i)), i)
+		}
+		var buf bytes.Buffer
+		require.NoError(t, c.Snapshot(&buf))
+
+		restored := NewShardedCache(4, 400)
+		require.NoError(t, restored.Restore(&buf))
+		require.ElementsMatch(t, c.Keys(), restored.Keys())
+		val, ok := restored.Get("7")
+		require.True(t, ok)
+		require.Equal(t, 7, val)
+	})
+}
@@ -0,0 +1,84 @@
+package hw04lrucache
+
+import "sync/atomic"
+
+// Stats is a snapshot of the cache counters.
+type Stats struct {
+	// Hits and Misses count the lookups by Get, not by Peek or Contains.
+	Hits   uint64
+	Misses uint64
+	// Evictions counts the entries removed to make room for new ones.
+	Evictions uint64
+	// Expirations counts the entries removed after their TTL passed.
+	Expirations uint64
+	// Overwrites counts the values set for keys already in the cache.
+	Overwrites uint64
+	// Entries is the number of entries, counting expired ones not removed yet.
+	Entries int
+	// Cost is the total cost of the entries.
+	Cost int64
+}
+
+// HitRatio returns the share of the lookups that found the value.
+func (s Stats) HitRatio() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+func (s Stats) add(other Stats) Stats {
+	s.Hits += other.Hits
+	s.Misses += other.Misses
+	s.Evictions += other.Evictions
+	s.Expirations += other.Expirations
+	s.Overwrites += other.Overwrites
+	s.Entries += other.Entries
+	s.Cost += other.Cost
+	return s
+}
+
+// counters are updated atomically, so that Stats reads them without the lock.
+type counters struct {
+	hits        atomic.Uint64
+	misses      atomic.Uint64
+	evictions   atomic.Uint64
+	expirations atomic.Uint64
+	overwrites  atomic.Uint64
+}
+
+func (c *counters) removed(reason EvictReason) {
+	switch reason {
+	case EvictCapacity:
+		c.evictions.Add(1)
+	case EvictExpired:
+		c.expirations.Add(1)
+	case EvictDeleted, EvictCleared:
+	}
+}
+
+// Stats returns the counters of the cache.
+func (lru *lruCache[K, V]) Stats() Stats {
+	lru.mu.Lock()
+	entries, cost := lru.policy.Len(), lru.cost
+	lru.mu.Unlock()
+
+	return Stats{
+		Hits:        lru.counters.hits.Load(),
+		Misses:      lru.counters.misses.Load(),
+		Evictions:   lru.counters.evictions.Load(),
+		Expirations: lru.counters.expirations.Load(),
+		Overwrites:  lru.counters.overwrites.Load(),
+		Entries:     entries,
+		Cost:        cost,
+	}
+}
+
+// Stats returns the counters of all the shards added together.
+func (sc *shardedCache[K, V]) Stats() Stats {
+	var s Stats
+	for _, shard := range sc.shards {
+		s = s.add(shard.Stats())
+	}
+	return s
+}
@@ -0,0 +1,123 @@
+package hw04lrucache
+
+import (
+	"bytes"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	//nolint:depguard
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats(t *testing.T) {
+	t.Run("counters", func(t *testing.T) {
+		c, clock := newTestCache(2)
+		c.Set("a", 1)
+		c.Set("a", 2)
+		c.SetWithTTL("b", 3, time.Second)
+		c.Get("a")
+		c.Get("x")
+		c.Peek("a")
+		c.Contains("x")
+
+		clock.Advance(time.Second)
+		c.Get("b")
+		c.SetWithTTL("b", 4, time.Second)
+		clock.Advance(time.Second)
+		c.Set("b", 5)
+		c.Set("c", 6)
+		c.Delete("c")
+
+		require.Equal(t, Stats{
+			Hits:        1,
+			Misses:      2,
+			Evictions:   1,
+			Expirations: 1,
+			Overwrites:  1,
+			Entries:     1,
+			Cost:        1,
+		}, c.Stats())
+		require.InDelta(t, 1.0/3, c.Stats().HitRatio(), 1e-9)
+		require.Zero(t, Stats{}.HitRatio())
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		c := NewCache(10)
+		var wg sync.WaitGroup
+		for g := 0; g < 4; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 1000; i++ {
+					key := Key(strconv.Itoa(i % 20))
+					if _, ok := c.Get(key); !ok {
+						c.Set(key, i)
+					}
+					c.Stats()
+				}
+			}()
+		}
+		wg.Wait()
+
+		s := c.Stats()
+		require.Equal(t, uint64(4000), s.Hits+s.Misses)
+		require.Equal(t, 10, s.Entries)
+	})
+
+	t.Run("sharded", func(t *testing.T) {
+		c := NewShardedCache(4, 100)
+		for i := 0; i < 10; i++ {
+			c.Set(Key(strconv.Itoa(i)), i)
+			c.Get(Key(strconv.Itoa(i)))
+		}
+		c.Get("missing")
+
+		s := c.Stats()
+		require.Equal(t, uint64(10), s.Hits)
+		require.Equal(t, uint64(1), s.Misses)
+		require.Equal(t, 10, s.Entries)
+	})
+}
+
+func TestWritePrometheus(t *testing.T) {
+	users := NewCache(1)
+	users.Set("a", 1)
+	users.Set("b", 2)
+	users.Get("b")
+	sessions := NewCache(1)
+	sessions.Get("a")
+
+	var buf bytes.Buffer
+	require.NoError(t, WritePrometheus(&buf, map[string]StatsProvider{"users": users, `s"e\s`: sessions}))
+	require.Equal(t, `# HELP cache_hits_total Lookups that found the value.
+# TYPE cache_hits_total counter
+cache_hits_total{cache="s\"e\\s"} 0
+cache_hits_total{cache="users"} 1
+# HELP cache_misses_total Lookups that did not find the value.
+# TYPE cache_misses_total counter
+cache_misses_total{cache="s\"e\\s"} 1
+cache_misses_total{cache="users"} 0
+# HELP cache_evictions_total Entries removed to make room for new ones.
+# TYPE cache_evictions_total counter
+cache_evictions_total{cache="s\"e\\s"} 0
+cache_evictions_total{cache="users"} 1
+# HELP cache_expirations_total Entries removed after their TTL passed.
+# TYPE cache_expirations_total counter
+cache_expirations_total{cache="s\"e\\s"} 0
+cache_expirations_total{cache="users"} 0
+# HELP cache_overwrites_total Values set for keys already in the cache.
+# TYPE cache_overwrites_total counter
+cache_overwrites_total{cache="s\"e\\s"} 0
+cache_overwrites_total{cache="users"} 0
+# HELP cache_entries Entries in the cache.
+# TYPE cache_entries gauge
+cache_entries{cache="s\"e\\s"} 0
+cache_entries{cache="users"} 1
+# HELP cache_cost Total cost of the entries in the cache.
+# TYPE cache_cost gauge
+cache_cost{cache="s\"e\\s"} 0
+cache_cost{cache="users"} 1
+`, buf.String())
+}